@@ -0,0 +1,63 @@
+package prtgapi
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const acknowledgeAlarmPath = "/api/acknowledgealarm.htm"
+
+// DefaultAlarmColumns are the columns that are fetched by Alarms when no columns are given in AlarmFilter
+var DefaultAlarmColumns = []string{"objid", "name", "device", "group", "status", "message", "lastvalue", "downtimesince"}
+
+// AlarmFilter can be used to filter the alarms returned by Alarms
+//
+// ParentID limits the alarms to the sensors below a device or group.
+// Acknowledged alarms are only returned when IncludeAcknowledged is set.
+type AlarmFilter struct {
+	ParentID            int64
+	Tags                []string
+	IncludeAcknowledged bool
+	Columns             []string
+}
+
+// Acknowledge acknowledges the alarm of a sensor that is down.
+//
+// With a duration of 0 the alarm is acknowledged indefinitely, otherwise PRTG
+// raises the alarm again after the duration, which is rounded up to whole minutes.
+func (s *SensorsService) Acknowledge(ctx context.Context, id int64, message string, duration time.Duration) error {
+	v := url.Values{}
+	v.Set("id", strconv.FormatInt(id, 10))
+	v.Set("ackmsg", message)
+	if duration != 0 {
+		minutes, err := durationMinutes(duration)
+		if err != nil {
+			return err
+		}
+		v.Set("duration", strconv.FormatInt(minutes, 10))
+	}
+	return s.client.do(ctx, acknowledgeAlarmPath, v, nil)
+}
+
+// Alarms returns the sensors that are currently down or partially down.
+// Acknowledged alarms are left out, unless IncludeAcknowledged is set in the filter.
+func (s *SensorsService) Alarms(ctx context.Context, filter AlarmFilter) ([]*Sensor, error) {
+	statuses := []Status{StatusDown, StatusDownPartial}
+	if filter.IncludeAcknowledged {
+		statuses = append(statuses, StatusDownAcknowledged)
+	}
+
+	columns := filter.Columns
+	if len(columns) == 0 {
+		columns = DefaultAlarmColumns
+	}
+
+	return s.List(ctx, SensorListOptions{
+		ID:      filter.ParentID,
+		Tags:    filter.Tags,
+		Status:  statuses,
+		Columns: columns,
+	})
+}
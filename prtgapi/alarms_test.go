@@ -0,0 +1,74 @@
+package prtgapi
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSensorsService_Acknowledge(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	var durations []string
+	mux.HandleFunc("/api/acknowledgealarm.htm", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testAuthentication(t, r)
+		testParams(t, r, map[string]string{
+			"id":     "2345",
+			"ackmsg": "Looking into it",
+		})
+		durations = append(durations, r.URL.Query().Get("duration"))
+		w.Header().Add("Content-Type", "text/html; charset=UTF-8")
+		w.WriteHeader(200)
+		w.Write([]byte(`<HTML><BODY class="no-content"><B class="no-content">OK</B></BODY></HTML>`))
+	})
+
+	ctx := context.Background()
+	err := client.Sensors().Acknowledge(ctx, 2345, "Looking into it", 0)
+	if err != nil {
+		t.Errorf("Error while acknowledging alarm: %v", err)
+	}
+	err = client.Sensors().Acknowledge(ctx, 2345, "Looking into it", 30*time.Minute)
+	if err != nil {
+		t.Errorf("Error while acknowledging alarm: %v", err)
+	}
+	if want := []string{"", "30"}; !reflect.DeepEqual(durations, want) {
+		t.Errorf("Expected durations %v, got %v", want, durations)
+	}
+}
+
+func TestSensorsService_Alarms(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/api/table.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testAuthentication(t, r)
+		testParams(t, r, map[string]string{
+			"content": "sensors",
+			"columns": "objid,name,device,group,status,message,lastvalue,downtimesince",
+			"id":      "900",
+		})
+		if statuses := r.URL.Query()["filter_status"]; !reflect.DeepEqual(statuses, []string{"5", "14", "13"}) {
+			t.Errorf("Expected status filters 5, 14 and 13, got %v", statuses)
+		}
+		w.Header().Add("Content-Type", "application/json; charset=UTF-8")
+		w.WriteHeader(200)
+		w.Write([]byte(`{"sensors": [{"objid": 2345, "name": "HTTP", "device": "testdevice", "status": "Down", "status_raw": 5, "message_raw": "Connection refused"}]}`))
+	})
+
+	ctx := context.Background()
+	got, err := client.Sensors().Alarms(ctx, AlarmFilter{ParentID: 900, IncludeAcknowledged: true})
+	if err != nil {
+		t.Fatalf("Error while getting alarms: %v", err)
+	}
+	want := []*Sensor{
+		&Sensor{ID: 2345, Name: "HTTP", Device: "testdevice", Status: StatusDown, StatusText: "Down", Message: "Connection refused"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Got %+v, expected %+v", got[0], want[0])
+	}
+}
@@ -0,0 +1,236 @@
+package prtgapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const channelListPath = "/api/table.json"
+
+type channelList struct {
+	Items []*Channel `json:"channels"`
+}
+
+// Channel represents a channel of a PRTG sensor
+//
+// LastValue is the value as formatted by PRTG, e.g. "128 msec", LastValueRaw holds the
+// number behind it. HasValue is false when the channel has no value yet, in which case
+// LastValueRaw and Unit are empty. The unit is taken from the formatted value.
+type Channel struct {
+	ID           int64 `json:"objid"`
+	SensorID     int64 `json:"-"`
+	Name         string
+	LastValue    string  `json:"lastvalue"`
+	LastValueRaw float64 `json:"-"`
+	HasValue     bool    `json:"-"`
+	Unit         string  `json:"-"`
+	Status       Status  `json:"status_raw"`
+	StatusText   string  `json:"status"`
+}
+
+// UnmarshalJSON decodes a channel row from a PRTG table, converting the raw value
+func (c *Channel) UnmarshalJSON(data []byte) error {
+	type channel Channel
+	aux := struct {
+		*channel
+		LastValueRaw flexFloat `json:"lastvalue_raw"`
+	}{
+		channel: (*channel)(c),
+	}
+
+	err := json.Unmarshal(data, &aux)
+	if err != nil {
+		return err
+	}
+
+	c.LastValueRaw = aux.LastValueRaw.Value
+	c.HasValue = aux.LastValueRaw.Valid
+	if c.HasValue {
+		c.Unit = unitFromValue(c.LastValue)
+	}
+
+	return nil
+}
+
+// Channels returns the channels of a sensor with their last values
+func (s *SensorsService) Channels(ctx context.Context, sensorID int64) ([]*Channel, error) {
+	v := url.Values{}
+	v.Set("content", "channels")
+	v.Set("columns", "objid,name,lastvalue,status")
+	v.Set("id", strconv.FormatInt(sensorID, 10))
+
+	channelList := &channelList{}
+	err := s.client.do(ctx, channelListPath, v, channelList)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, channel := range channelList.Items {
+		channel.SensorID = sensorID
+	}
+
+	return channelList.Items, nil
+}
+
+// unitFromValue returns the unit of a value formatted by PRTG, e.g. "msec" for "1.024 msec".
+// PRTG may separate the value and unit with a non-breaking space.
+func unitFromValue(value string) string {
+	return strings.TrimSpace(strings.TrimLeft(value, "0123456789.,-+< \u00a0"))
+}
+
+// ChannelLimits holds the limits of a sensor channel
+//
+// PRTG sets the channel to the warning or error state when its value crosses one of the limits.
+// Nil limits are not set. When Enabled is false the limits are switched off and the other fields
+// are ignored by SetChannelLimits.
+type ChannelLimits struct {
+	Enabled        bool
+	MaxError       *float64
+	MaxWarning     *float64
+	MinError       *float64
+	MinWarning     *float64
+	ErrorMessage   string
+	WarningMessage string
+}
+
+// GetChannelProperty returns the current value of a property of a sensor channel
+func (s *SensorsService) GetChannelProperty(ctx context.Context, sensorID int64, channelID int64, name string) (string, error) {
+	v := url.Values{}
+	v.Set("id", strconv.FormatInt(sensorID, 10))
+	v.Set("subtype", "channel")
+	v.Set("subid", strconv.FormatInt(channelID, 10))
+	v.Set("name", name)
+
+	var propertyResult struct {
+		Value string `xml:"result"`
+	}
+
+	err := s.client.do(ctx, getSensorObjectPropertyPath, v, &propertyResult)
+	if err != nil {
+		return "", err
+	}
+
+	return propertyResult.Value, nil
+}
+
+// UpdateChannelProperty sets a new value for a property of a sensor channel
+func (s *SensorsService) UpdateChannelProperty(ctx context.Context, sensorID int64, channelID int64, name string, value string) error {
+	v := url.Values{}
+	v.Set("id", strconv.FormatInt(sensorID, 10))
+	v.Set("subtype", "channel")
+	v.Set("subid", strconv.FormatInt(channelID, 10))
+	v.Set("name", name)
+	v.Set("value", value)
+	return s.client.do(ctx, setSensorObjectPropertyPath, v, nil)
+}
+
+// GetChannelLimits returns the limits of a sensor channel, the properties are read concurrently
+func (s *SensorsService) GetChannelLimits(ctx context.Context, sensorID int64, channelID int64) (*ChannelLimits, error) {
+	get := func(ctx context.Context, id int64, name string) (string, error) {
+		return s.GetChannelProperty(ctx, id, channelID, name)
+	}
+
+	values, err := getProperties(ctx, get, sensorID, []string{
+		"limitmode", "limitmaxerror", "limitmaxwarning", "limitminerror", "limitminwarning", "limiterrormsg", "limitwarningmsg",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	limits := &ChannelLimits{
+		Enabled:        values["limitmode"] == "1",
+		ErrorMessage:   values["limiterrormsg"],
+		WarningMessage: values["limitwarningmsg"],
+	}
+
+	for name, limit := range map[string]**float64{
+		"limitmaxerror":   &limits.MaxError,
+		"limitmaxwarning": &limits.MaxWarning,
+		"limitminerror":   &limits.MinError,
+		"limitminwarning": &limits.MinWarning,
+	} {
+		if values[name] == "" {
+			continue
+		}
+		value, err := strconv.ParseFloat(values[name], 64)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to parse %s of channel %d of sensor %d: %v", name, channelID, sensorID, err)
+		}
+		*limit = &value
+	}
+
+	return limits, nil
+}
+
+// SetChannelLimits sets the limits of a sensor channel.
+// The minimum limits must be below the maximum limits and the warning limits between the error limits.
+func (s *SensorsService) SetChannelLimits(ctx context.Context, sensorID int64, channelID int64, limits ChannelLimits) error {
+	if !limits.Enabled {
+		return s.UpdateChannelProperty(ctx, sensorID, channelID, "limitmode", "0")
+	}
+
+	err := limits.validate()
+	if err != nil {
+		return err
+	}
+
+	properties := []struct {
+		name  string
+		value string
+	}{
+		{"limitmaxerror", formatLimit(limits.MaxError)},
+		{"limitmaxwarning", formatLimit(limits.MaxWarning)},
+		{"limitminerror", formatLimit(limits.MinError)},
+		{"limitminwarning", formatLimit(limits.MinWarning)},
+		{"limiterrormsg", limits.ErrorMessage},
+		{"limitwarningmsg", limits.WarningMessage},
+		{"limitmode", "1"},
+	}
+	for _, property := range properties {
+		err = s.UpdateChannelProperty(ctx, sensorID, channelID, property.name, property.value)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validate checks that the limits that are set are in order from the minimum error limit
+// up to the maximum error limit, so the warning limits are between the error limits.
+func (l ChannelLimits) validate() error {
+	limits := []struct {
+		name  string
+		value *float64
+	}{
+		{"minimum error", l.MinError},
+		{"minimum warning", l.MinWarning},
+		{"maximum warning", l.MaxWarning},
+		{"maximum error", l.MaxError},
+	}
+
+	for i, lower := range limits {
+		for _, upper := range limits[i+1:] {
+			if lower.value != nil && upper.value != nil && *lower.value >= *upper.value {
+				return fmt.Errorf("The %s limit (%v) must be below the %s limit (%v)", lower.name, *lower.value, upper.name, *upper.value)
+			}
+		}
+	}
+
+	if l.MinError == nil && l.MinWarning == nil && l.MaxError == nil && l.MaxWarning == nil {
+		return fmt.Errorf("At least one limit is required when the limits are enabled")
+	}
+
+	return nil
+}
+
+func formatLimit(limit *float64) string {
+	if limit == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*limit, 'f', -1, 64)
+}
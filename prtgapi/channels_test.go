@@ -0,0 +1,162 @@
+package prtgapi
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestSensorsService_Channels(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/api/table.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testAuthentication(t, r)
+		testParams(t, r, map[string]string{
+			"content": "channels",
+			"id":      "2345",
+		})
+		w.Header().Add("Content-Type", "application/json; charset=UTF-8")
+		w.WriteHeader(200)
+		w.Write(channelListJSON)
+	})
+
+	ctx := context.Background()
+	got, err := client.Sensors().Channels(ctx, 2345)
+	if err != nil {
+		t.Fatalf("Error while getting channels: %v", err)
+	}
+	want := []*Channel{
+		&Channel{ID: 2, SensorID: 2345, Name: "Response Time", LastValue: "1.024 msec", LastValueRaw: 1024, HasValue: true, Unit: "msec", Status: StatusUp, StatusText: "Up"},
+		&Channel{ID: 3, SensorID: 2345, Name: "Days to Expiration", LastValue: "87 # days", LastValueRaw: 87, HasValue: true, Unit: "# days"},
+		&Channel{ID: -4, SensorID: 2345, Name: "Downtime", LastValue: ""},
+	}
+	if !reflect.DeepEqual(got, want) {
+		for i := range got {
+			t.Errorf("Got %+v", got[i])
+		}
+	}
+}
+
+var channelListJSON = []byte(`{
+	"prtg-version": "19.4.53.1912",
+	"treesize": 3,
+	"channels": [
+		{
+			"objid": 2,
+			"name": "Response Time",
+			"lastvalue": "1.024 msec",
+			"lastvalue_raw": 1024.0000,
+			"status": "Up",
+			"status_raw": 3
+		},
+		{
+			"objid": 3,
+			"name": "Days to Expiration",
+			"lastvalue": "87 # days",
+			"lastvalue_raw": "87"
+		},
+		{
+			"objid": -4,
+			"name": "Downtime",
+			"lastvalue": "",
+			"lastvalue_raw": ""
+		}
+	]
+}`)
+
+func TestSensorsService_GetChannelLimits(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	newPropertyStore(t, mux, map[string]map[string]string{"2345/2": {
+		"limitmode":       "1",
+		"limitmaxerror":   "500",
+		"limitmaxwarning": "250.5",
+		"limiterrormsg":   "Too slow",
+	}})
+
+	ctx := context.Background()
+	got, err := client.Sensors().GetChannelLimits(ctx, 2345, 2)
+	if err != nil {
+		t.Fatalf("Error while getting channel limits: %v", err)
+	}
+	maxError, maxWarning := 500.0, 250.5
+	want := &ChannelLimits{Enabled: true, MaxError: &maxError, MaxWarning: &maxWarning, ErrorMessage: "Too slow"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Got %+v, expected %+v", got, want)
+	}
+}
+
+func TestSensorsService_SetChannelLimits(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	store := newPropertyStore(t, mux, nil)
+
+	ctx := context.Background()
+	minError, minWarning := 7.0, 14.0
+	err := client.Sensors().SetChannelLimits(ctx, 2345, 3, ChannelLimits{
+		Enabled:    true,
+		MinError:   &minError,
+		MinWarning: &minWarning,
+	})
+	if err != nil {
+		t.Fatalf("Error while setting channel limits: %v", err)
+	}
+	want := map[string]string{
+		"limitmode":       "1",
+		"limitmaxerror":   "",
+		"limitmaxwarning": "",
+		"limitminerror":   "7",
+		"limitminwarning": "14",
+		"limiterrormsg":   "",
+		"limitwarningmsg": "",
+	}
+	if written := store.written()["2345/3"]; !reflect.DeepEqual(written, want) {
+		t.Errorf("Expected %v to be written, got %v", want, written)
+	}
+
+	maxWarning := 10.0
+	err = client.Sensors().SetChannelLimits(ctx, 2345, 3, ChannelLimits{
+		Enabled:    true,
+		MinWarning: &minWarning,
+		MaxWarning: &maxWarning,
+	})
+	if err == nil {
+		t.Errorf("Expected an error when the minimum limit is above the maximum limit")
+	}
+}
+
+func TestChannelLimits_Validate(t *testing.T) {
+	limit := func(value float64) *float64 {
+		return &value
+	}
+
+	tests := []struct {
+		limits ChannelLimits
+		err    string
+	}{
+		{ChannelLimits{MinError: limit(5), MinWarning: limit(10), MaxWarning: limit(90), MaxError: limit(95)}, ""},
+		{ChannelLimits{MaxError: limit(95)}, ""},
+		{ChannelLimits{}, "At least one limit is required when the limits are enabled"},
+		{ChannelLimits{MinError: limit(10), MinWarning: limit(5)}, "The minimum error limit (10) must be below the minimum warning limit (5)"},
+		{ChannelLimits{MaxWarning: limit(95), MaxError: limit(90)}, "The maximum warning limit (95) must be below the maximum error limit (90)"},
+		{ChannelLimits{MinWarning: limit(50), MaxError: limit(40)}, "The minimum warning limit (50) must be below the maximum error limit (40)"},
+		// The first pair out of order is reported
+		{ChannelLimits{MinError: limit(100), MinWarning: limit(90), MaxWarning: limit(80), MaxError: limit(70)}, "The minimum error limit (100) must be below the minimum warning limit (90)"},
+	}
+
+	for _, tt := range tests {
+		err := tt.limits.validate()
+		got := ""
+		if err != nil {
+			got = err.Error()
+		}
+		if got != tt.err {
+			t.Errorf("Got error %q for %+v, expected %q", got, tt.limits, tt.err)
+		}
+	}
+}
@@ -9,9 +9,16 @@ import (
 	"mime"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
+	"time"
 )
 
+// cleanupTimeout limits how long undoing part of a failed or finished operation may take,
+// e.g. deleting a device that couldn't be set up. Cleanups don't use the caller's context,
+// as that may have expired and be the reason the cleanup is needed.
+const cleanupTimeout = 30 * time.Second
+
 // Client holds the PRTG api client
 // Use NewClient to create a new client
 type Client struct {
@@ -22,14 +29,36 @@ type Client struct {
 	UserAgent  string
 	HTTPClient *http.Client
 
-	devicesService *DevicesService
-	sensorsService *SensorsService
+	// Location is the timezone of the PRTG core server, which PRTG uses to interpret
+	// dates without a timezone, e.g. maintenance windows. NewClient sets it to time.Local.
+	Location *time.Location
+
+	devicesService  *DevicesService
+	groupsService   *GroupsService
+	sensorsService  *SensorsService
+	triggersService *TriggersService
 }
 
 type redirectResponse struct {
 	Location string
 }
 
+// objectID returns the ID of the object PRTG redirected to.
+// PRTG uses this to give back the ID of newly created objects.
+func (r *redirectResponse) objectID() (int64, error) {
+	location, err := url.Parse(r.Location)
+	if err != nil {
+		return 0, err
+	}
+
+	id := location.Query().Get("id")
+	if id == "" {
+		return 0, fmt.Errorf("No object ID found in the redirect location %q", r.Location)
+	}
+
+	return strconv.ParseInt(id, 10, 64)
+}
+
 // NewClient creates a new PRTG api client
 func NewClient(url url.URL, username string, passhash string, userAgent string, httpClient *http.Client) *Client {
 	client := &Client{
@@ -38,6 +67,7 @@ func NewClient(url url.URL, username string, passhash string, userAgent string,
 		Passhash:   passhash,
 		UserAgent:  userAgent,
 		HTTPClient: httpClient,
+		Location:   time.Local,
 	}
 
 	if client.HTTPClient == nil {
@@ -53,7 +83,9 @@ func NewClient(url url.URL, username string, passhash string, userAgent string,
 	}
 
 	client.devicesService = NewDevicesService(client)
+	client.groupsService = NewGroupsService(client)
 	client.sensorsService = NewSensorsService(client)
+	client.triggersService = NewTriggersService(client)
 
 	return client
 }
@@ -63,12 +95,24 @@ func (client *Client) Devices() *DevicesService {
 	return client.devicesService
 }
 
+// Groups provides access to the API actions that apply to groups
+func (client *Client) Groups() *GroupsService {
+	return client.groupsService
+}
+
 // Sensors provides access to the API actions that apply to devices
 func (client *Client) Sensors() *SensorsService {
 	return client.sensorsService
 }
 
-func (client *Client) do(ctx context.Context, path string, values url.Values, v interface{}) error {
+// Triggers provides access to the API actions that apply to notification triggers
+func (client *Client) Triggers() *TriggersService {
+	return client.triggersService
+}
+
+// request sends an authenticated request to PRTG and returns the response.
+// The caller is responsible for closing the body of the response.
+func (client *Client) request(ctx context.Context, path string, values url.Values) (*http.Response, error) {
 	values.Set("username", client.Username)
 	values.Set("passhash", client.Passhash)
 
@@ -78,18 +122,46 @@ func (client *Client) do(ctx context.Context, path string, values url.Values, v
 
 	req, err := http.NewRequestWithContext(ctx, "GET", url.String(), nil)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	res, err := client.HTTPClient.Do(req)
 	if err != nil {
-		return err
+		return nil, redactURLError(err)
 	}
-	defer res.Body.Close()
 
 	if res.StatusCode == http.StatusForbidden {
-		return fmt.Errorf("Error while authenticating to PRTG")
+		res.Body.Close()
+		return nil, fmt.Errorf("Error while authenticating to PRTG")
+	}
+
+	return res, nil
+}
+
+// location returns the timezone of the PRTG core server
+func (client *Client) location() *time.Location {
+	if client.Location == nil {
+		return time.Local
+	}
+	return client.Location
+}
+
+// formatTime formats a time in the format and timezone PRTG expects in properties and query parameters
+func (client *Client) formatTime(t time.Time) string {
+	return t.In(client.location()).Format(prtgTimeFormat)
+}
+
+// parseTime parses a time in the format PRTG uses, in the timezone of the PRTG core server
+func (client *Client) parseTime(value string) (time.Time, error) {
+	return time.ParseInLocation(prtgTimeFormat, value, client.location())
+}
+
+func (client *Client) do(ctx context.Context, path string, values url.Values, v interface{}) error {
+	res, err := client.request(ctx, path, values)
+	if err != nil {
+		return err
 	}
+	defer res.Body.Close()
 
 	if res.StatusCode == http.StatusFound {
 		redirect, ok := v.(*redirectResponse)
@@ -104,6 +176,13 @@ func (client *Client) do(ctx context.Context, path string, values url.Values, v
 		return fmt.Errorf("Got a non-200 response from PRTG. Status %d", res.StatusCode)
 	}
 
+	// Actions are answered with an HTML page that holds nothing of interest. The body isn't
+	// read when there's nothing to decode it into, as pages like the add sensor wizard aren't
+	// valid XML and would fail the action after PRTG already performed it.
+	if _, ok := v.(*redirectResponse); ok || v == nil {
+		return nil
+	}
+
 	body, err := ioutil.ReadAll(res.Body)
 	if err != nil {
 		return err
@@ -130,6 +209,27 @@ func (client *Client) do(ctx context.Context, path string, values url.Values, v
 	return nil
 }
 
+// redactURLError removes the query from the URL in errors of the HTTP client.
+// The query holds the passhash and the values of properties, which may be passwords.
+func redactURLError(err error) error {
+	urlErr, ok := err.(*url.Error)
+	if !ok {
+		return err
+	}
+
+	u, parseErr := url.Parse(urlErr.URL)
+	if parseErr != nil {
+		urlErr.URL = "[redacted]"
+		return urlErr
+	}
+	if u.RawQuery != "" {
+		u.RawQuery = "[redacted]"
+	}
+	urlErr.URL = u.String()
+
+	return urlErr
+}
+
 func isJSON(contentType string) (bool, error) {
 	for _, c := range strings.Split(contentType, ",") {
 		mediatype, _, err := mime.ParseMediaType(c)
@@ -142,3 +242,19 @@ func isJSON(contentType string) (bool, error) {
 	}
 	return false, nil
 }
+
+// columnsParam builds the value of the columns parameter for table.json,
+// making sure the objid column is always requested
+func columnsParam(columns []string, defaults []string) string {
+	if len(columns) == 0 {
+		columns = defaults
+	}
+
+	for _, column := range columns {
+		if column == "objid" {
+			return strings.Join(columns, ",")
+		}
+	}
+
+	return strings.Join(append([]string{"objid"}, columns...), ",")
+}
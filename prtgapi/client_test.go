@@ -1,10 +1,13 @@
 package prtgapi
 
 import (
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"sync"
 	"testing"
+	"time"
 )
 
 func setup() (client *Client, mux *http.ServeMux, serverURL string, teardown func()) {
@@ -12,6 +15,8 @@ func setup() (client *Client, mux *http.ServeMux, serverURL string, teardown fun
 	srv := httptest.NewServer(mux)
 	u, _ := url.Parse(srv.URL)
 	client = NewClient(*u, "testsuite", "987654321", "prtgapi testsuite", srv.Client())
+	// Keep the tests independent of the timezone of the machine they run on
+	client.Location = time.UTC
 
 	return client, mux, srv.URL, srv.Close
 }
@@ -37,3 +42,101 @@ func testAuthentication(t *testing.T, request *http.Request) {
 		t.Errorf("Expected authentication with username testsuite and passhash 987654321, got username %s and passhash %s", username, passhash)
 	}
 }
+
+// propertyStore fakes the getobjectproperty and setobjectproperty endpoints of PRTG.
+//
+// Properties are keyed on object ID and property name. Channel properties are keyed
+// on "<sensor id>/<channel id>". Written values are recorded and served by later reads.
+type propertyStore struct {
+	mu         sync.Mutex
+	properties map[string]map[string]string
+	writes     map[string]map[string]string
+	reads      map[string][]string
+}
+
+func newPropertyStore(t *testing.T, mux *http.ServeMux, properties map[string]map[string]string) *propertyStore {
+	store := &propertyStore{
+		properties: map[string]map[string]string{},
+		writes:     map[string]map[string]string{},
+		reads:      map[string][]string{},
+	}
+	for id, values := range properties {
+		for name, value := range values {
+			store.set(store.properties, id, name, value)
+		}
+	}
+
+	mux.HandleFunc("/api/getobjectproperty.htm", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testAuthentication(t, r)
+		id, name := propertyStoreKey(r), r.URL.Query().Get("name")
+
+		store.mu.Lock()
+		store.reads[id] = append(store.reads[id], name)
+		value := store.properties[id][name]
+		store.mu.Unlock()
+
+		w.Header().Add("Content-Type", "text/xml; charset=UTF-8")
+		w.WriteHeader(200)
+		fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8" ?><prtg><version>19.4.53.1912</version><result>%s</result></prtg>`, value)
+	})
+
+	mux.HandleFunc("/api/setobjectproperty.htm", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testAuthentication(t, r)
+		id, name, value := propertyStoreKey(r), r.URL.Query().Get("name"), r.URL.Query().Get("value")
+
+		store.mu.Lock()
+		store.set(store.properties, id, name, value)
+		store.set(store.writes, id, name, value)
+		store.mu.Unlock()
+
+		w.Header().Add("Content-Type", "text/html; charset=UTF-8")
+		w.WriteHeader(200)
+		w.Write([]byte(`<HTML><BODY class="no-content"><B class="no-content">OK</B></BODY></HTML>`))
+	})
+
+	return store
+}
+
+func propertyStoreKey(r *http.Request) string {
+	id := r.URL.Query().Get("id")
+	if r.URL.Query().Get("subtype") == "channel" {
+		id += "/" + r.URL.Query().Get("subid")
+	}
+	return id
+}
+
+func (p *propertyStore) set(properties map[string]map[string]string, id string, name string, value string) {
+	if properties[id] == nil {
+		properties[id] = map[string]string{}
+	}
+	properties[id][name] = value
+}
+
+// written returns the values written so far, keyed on object ID and property name
+func (p *propertyStore) written() map[string]map[string]string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	written := map[string]map[string]string{}
+	for id, values := range p.writes {
+		for name, value := range values {
+			p.set(written, id, name, value)
+		}
+	}
+	return written
+}
+
+// read returns whether a property of an object has been read
+func (p *propertyStore) read(id string, name string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, read := range p.reads[id] {
+		if read == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,165 @@
+package prtgapi
+
+import (
+	"context"
+	"fmt"
+)
+
+// CredentialType selects one of the credential sections of a device or group
+type CredentialType int
+
+// The credential types that can be managed through SetCredentials
+const (
+	CredentialsWindows CredentialType = iota + 1
+	CredentialsLinux
+	CredentialsHTTP
+)
+
+// credentialProperties holds the PRTG property names of a credential section
+type credentialProperties struct {
+	inherit  string
+	mode     string
+	domain   string
+	username string
+	password string
+}
+
+var credentialPropertyNames = map[CredentialType]credentialProperties{
+	CredentialsWindows: {
+		inherit:  "windowsconnection",
+		domain:   "windowslogindomain",
+		username: "windowsloginusername",
+		password: "windowsloginpassword",
+	},
+	CredentialsLinux: {
+		inherit:  "linuxconnection",
+		mode:     "linuxloginmode",
+		username: "linuxloginusername",
+		password: "linuxloginpassword",
+	},
+	CredentialsHTTP: {
+		inherit:  "httpconnection",
+		username: "httpusername",
+		password: "httppassword",
+	},
+}
+
+// Credentials holds the credentials that are set on a device or group
+//
+// When Inherit is set the credentials are taken from the parent object and the other
+// fields are ignored. Domain is only used for Windows credentials.
+// The password is never included when the credentials are formatted or encoded.
+type Credentials struct {
+	Inherit  bool
+	Domain   string
+	Username string
+	Password string `json:"-"`
+}
+
+func (c Credentials) String() string {
+	return fmt.Sprintf("{Inherit:%t Domain:%s Username:%s Password:[redacted]}", c.Inherit, c.Domain, c.Username)
+}
+
+// GoString makes sure the password isn't printed with the %#v verb
+func (c Credentials) GoString() string {
+	return "prtgapi.Credentials" + c.String()
+}
+
+// CredentialStatus describes the credentials of an object without exposing the password
+//
+// The API of PRTG has no way to tell whether a password is set other than reading the
+// password itself, so only the inheritance and the username are reported. A username
+// without a password can't be told apart from complete credentials.
+type CredentialStatus struct {
+	Inherited bool
+	Username  string
+}
+
+// UsernameConfigured returns whether the credentials are inherited or a username is set on
+// the object itself. It doesn't check the password, see CredentialStatus.
+func (s CredentialStatus) UsernameConfigured() bool {
+	return s.Inherited || s.Username != ""
+}
+
+// SetCredentials sets the credentials of the given type on a device
+func (d *DevicesService) SetCredentials(ctx context.Context, id int64, credentialType CredentialType, credentials Credentials) error {
+	return setCredentials(ctx, d.UpdateProperty, id, credentialType, credentials)
+}
+
+// GetCredentialStatus returns the inheritance and username of the credentials of the given type on a device
+func (d *DevicesService) GetCredentialStatus(ctx context.Context, id int64, credentialType CredentialType) (*CredentialStatus, error) {
+	return getCredentialStatus(ctx, d.GetProperty, id, credentialType)
+}
+
+// SetCredentials sets the credentials of the given type on a group
+func (g *GroupsService) SetCredentials(ctx context.Context, id int64, credentialType CredentialType, credentials Credentials) error {
+	return setCredentials(ctx, g.UpdateProperty, id, credentialType, credentials)
+}
+
+// GetCredentialStatus returns the inheritance and username of the credentials of the given type on a group
+func (g *GroupsService) GetCredentialStatus(ctx context.Context, id int64, credentialType CredentialType) (*CredentialStatus, error) {
+	return getCredentialStatus(ctx, g.GetProperty, id, credentialType)
+}
+
+func setCredentials(ctx context.Context, set propertySetter, id int64, credentialType CredentialType, credentials Credentials) error {
+	properties, ok := credentialPropertyNames[credentialType]
+	if !ok {
+		return fmt.Errorf("Unknown credential type %d", credentialType)
+	}
+
+	if credentials.Inherit {
+		return set(ctx, id, properties.inherit, "1")
+	}
+
+	if credentials.Username == "" {
+		return fmt.Errorf("A username is required when the credentials are not inherited")
+	}
+	if credentials.Domain != "" && properties.domain == "" {
+		return fmt.Errorf("A domain can only be set on Windows credentials")
+	}
+
+	if properties.domain != "" {
+		err := set(ctx, id, properties.domain, credentials.Domain)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Select login with a password instead of a private key
+	if properties.mode != "" {
+		err := set(ctx, id, properties.mode, "0")
+		if err != nil {
+			return err
+		}
+	}
+
+	err := set(ctx, id, properties.username, credentials.Username)
+	if err != nil {
+		return err
+	}
+
+	err = set(ctx, id, properties.password, credentials.Password)
+	if err != nil {
+		return err
+	}
+
+	return set(ctx, id, properties.inherit, "0")
+}
+
+// getCredentialStatus never reads the password property, so the secret isn't sent back by PRTG
+func getCredentialStatus(ctx context.Context, get propertyGetter, id int64, credentialType CredentialType) (*CredentialStatus, error) {
+	properties, ok := credentialPropertyNames[credentialType]
+	if !ok {
+		return nil, fmt.Errorf("Unknown credential type %d", credentialType)
+	}
+
+	values, err := getProperties(ctx, get, id, []string{properties.inherit, properties.username})
+	if err != nil {
+		return nil, err
+	}
+
+	return &CredentialStatus{
+		Inherited: values[properties.inherit] == "1",
+		Username:  values[properties.username],
+	}, nil
+}
@@ -0,0 +1,90 @@
+package prtgapi
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGroupsService_SetCredentials(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	store := newPropertyStore(t, mux, nil)
+
+	ctx := context.Background()
+	err := client.Groups().SetCredentials(ctx, 1000, CredentialsLinux, Credentials{
+		Username: "monitoring",
+		Password: "s3cret",
+	})
+	if err != nil {
+		t.Fatalf("Error while setting credentials: %v", err)
+	}
+	want := map[string]map[string]string{
+		"1000": {
+			"linuxloginmode":     "0",
+			"linuxloginusername": "monitoring",
+			"linuxloginpassword": "s3cret",
+			"linuxconnection":    "0",
+		},
+	}
+	if written := store.written(); !reflect.DeepEqual(written, want) {
+		t.Errorf("Expected %v to be written, got %v", want, written)
+	}
+
+	err = client.Groups().SetCredentials(ctx, 1000, CredentialsLinux, Credentials{Domain: "example", Username: "monitoring"})
+	if err == nil {
+		t.Errorf("Expected an error when setting a domain on Linux credentials")
+	}
+}
+
+func TestDevicesService_GetCredentialStatus(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	store := newPropertyStore(t, mux, map[string]map[string]string{
+		"1234": {
+			"windowsconnection":    "0",
+			"windowsloginusername": "monitoring",
+			"windowsloginpassword": "***",
+		},
+	})
+
+	ctx := context.Background()
+	got, err := client.Devices().GetCredentialStatus(ctx, 1234, CredentialsWindows)
+	if err != nil {
+		t.Fatalf("Error while getting credential status: %v", err)
+	}
+	if want := (&CredentialStatus{Username: "monitoring"}); !reflect.DeepEqual(got, want) {
+		t.Errorf("Got %+v, expected %+v", got, want)
+	}
+	if !got.UsernameConfigured() {
+		t.Errorf("Expected a username to be configured")
+	}
+	if store.read("1234", "windowsloginpassword") {
+		t.Errorf("Expected the password not to be read")
+	}
+}
+
+func TestCredentials_Redacted(t *testing.T) {
+	credentials := Credentials{Username: "monitoring", Password: "s3cret"}
+	for _, format := range []string{"%v", "%+v", "%#v", "%s"} {
+		if formatted := fmt.Sprintf(format, credentials); strings.Contains(formatted, "s3cret") {
+			t.Errorf("Password is visible when formatting with %s: %s", format, formatted)
+		}
+	}
+
+	// Errors of the HTTP client include the URL, which holds the password as a query parameter
+	u, _ := url.Parse("http://127.0.0.1:1")
+	client := NewClient(*u, "testsuite", "987654321", "prtgapi testsuite", nil)
+	err := client.Devices().SetCredentials(context.Background(), 1234, CredentialsWindows, Credentials{Username: "monitoring", Password: "s3cret"})
+	if err == nil {
+		t.Fatalf("Expected an error when PRTG can't be reached")
+	}
+	if strings.Contains(err.Error(), "s3cret") || strings.Contains(err.Error(), "987654321") {
+		t.Errorf("Error contains secret values: %v", err)
+	}
+}
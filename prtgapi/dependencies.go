@@ -0,0 +1,190 @@
+package prtgapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// ErrCircularDependency is returned when setting a dependency would make an object depend on itself
+var ErrCircularDependency = errors.New("Circular dependency")
+
+// Dependency describes which object an object depends on
+//
+// ObjectID is only used with DependencyObject. Delay is the time PRTG waits
+// before resuming the monitoring of the object after its dependency is up again.
+type Dependency struct {
+	Type     DependencyType
+	ObjectID int64
+	Delay    time.Duration
+}
+
+// GetDependency returns the dependency of a device
+func (d *DevicesService) GetDependency(ctx context.Context, id int64) (*Dependency, error) {
+	return getDependency(ctx, d.GetProperty, id)
+}
+
+// SetDependency sets the dependency of a device.
+// Devices can't use DependencyMaster, that is reserved for sensors.
+func (d *DevicesService) SetDependency(ctx context.Context, id int64, dependency Dependency) error {
+	return setDependency(ctx, d.client, d.GetProperty, d.UpdateProperty, id, dependency, false)
+}
+
+// GetDependency returns the dependency of a group
+func (g *GroupsService) GetDependency(ctx context.Context, id int64) (*Dependency, error) {
+	return getDependency(ctx, g.GetProperty, id)
+}
+
+// SetDependency sets the dependency of a group.
+// Groups can't use DependencyMaster, that is reserved for sensors.
+func (g *GroupsService) SetDependency(ctx context.Context, id int64, dependency Dependency) error {
+	return setDependency(ctx, g.client, g.GetProperty, g.UpdateProperty, id, dependency, false)
+}
+
+// GetDependency returns the dependency of a sensor
+func (s *SensorsService) GetDependency(ctx context.Context, id int64) (*Dependency, error) {
+	return getDependency(ctx, s.GetProperty, id)
+}
+
+// SetDependency sets the dependency of a sensor
+func (s *SensorsService) SetDependency(ctx context.Context, id int64, dependency Dependency) error {
+	return setDependency(ctx, s.client, s.GetProperty, s.UpdateProperty, id, dependency, true)
+}
+
+func getDependency(ctx context.Context, get propertyGetter, id int64) (*Dependency, error) {
+	values, err := getProperties(ctx, get, id, []string{"dependencytype", "dependency", "depdelay"})
+	if err != nil {
+		return nil, err
+	}
+
+	dependency := &Dependency{}
+
+	if value := values["dependencytype"]; value != "" {
+		t, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to parse the dependency type of object %d: %v", id, err)
+		}
+		dependency.Type = DependencyType(t)
+	}
+
+	if value := values["dependency"]; value != "" && dependency.Type == DependencyObject {
+		dependency.ObjectID, err = strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to parse the dependency of object %d: %v", id, err)
+		}
+	}
+
+	if value := values["depdelay"]; value != "" {
+		seconds, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to parse the dependency delay of object %d: %v", id, err)
+		}
+		dependency.Delay = time.Duration(seconds) * time.Second
+	}
+
+	return dependency, nil
+}
+
+func setDependency(ctx context.Context, client *Client, get propertyGetter, set propertySetter, id int64, dependency Dependency, allowMaster bool) error {
+	switch dependency.Type {
+	case DependencyParent:
+	case DependencyObject:
+		if dependency.ObjectID == 0 {
+			return fmt.Errorf("A dependency on an object requires an object ID")
+		}
+		err := checkCircularDependency(ctx, client, get, id, dependency.ObjectID)
+		if err != nil {
+			return err
+		}
+	case DependencyMaster:
+		if !allowMaster {
+			return fmt.Errorf("Only sensors can be the master object of their parent")
+		}
+	default:
+		return fmt.Errorf("Unknown dependency type %d", dependency.Type)
+	}
+
+	if dependency.Delay < 0 {
+		return fmt.Errorf("The dependency delay can't be negative")
+	}
+
+	err := set(ctx, id, "dependencytype", strconv.Itoa(int(dependency.Type)))
+	if err != nil {
+		return err
+	}
+
+	if dependency.Type == DependencyObject {
+		err = set(ctx, id, "dependency", strconv.FormatInt(dependency.ObjectID, 10))
+		if err != nil {
+			return err
+		}
+	}
+
+	return set(ctx, id, "depdelay", strconv.FormatInt(int64(dependency.Delay/time.Second), 10))
+}
+
+// checkCircularDependency follows the dependencies starting at target and returns
+// ErrCircularDependency when the chain leads back to id, or passes an object below id.
+// Objects below id depend on it through their parents, so depending on one of them is
+// circular as well. Objects that use the dependency of their parent continue the chain
+// at their parent, which is looked up in the object tree of the whole installation.
+//
+// The properties of every object can be read through the same endpoint,
+// so the getter of any service can follow chains that cross object types.
+func checkCircularDependency(ctx context.Context, client *Client, get propertyGetter, id int64, target int64) error {
+	tree, err := client.Tree(ctx, 0)
+	if err != nil {
+		return err
+	}
+	object := tree.FindByID(id)
+	if object == nil {
+		return fmt.Errorf("Object %d was not found in the object tree", id)
+	}
+
+	visited := map[int64]bool{id: true}
+
+	for current := target; ; {
+		if visited[current] {
+			return fmt.Errorf("%w: making object %d depend on object %d leads back to object %d", ErrCircularDependency, id, target, current)
+		}
+		visited[current] = true
+
+		if object.FindByID(current) != nil {
+			return fmt.Errorf("%w: making object %d depend on object %d leads to object %d, which is below object %d", ErrCircularDependency, id, target, current, id)
+		}
+
+		dependencyType, err := get(ctx, current, "dependencytype")
+		if err != nil {
+			return err
+		}
+
+		switch dependencyType {
+		case strconv.Itoa(int(DependencyObject)):
+			next, err := get(ctx, current, "dependency")
+			if err != nil {
+				return err
+			}
+			if next == "" {
+				return nil
+			}
+			nextID, err := strconv.ParseInt(next, 10, 64)
+			if err != nil {
+				return fmt.Errorf("Unable to parse the dependency of object %d: %v", current, err)
+			}
+			current = nextID
+		case "", strconv.Itoa(int(DependencyParent)):
+			node := tree.FindByID(current)
+			if node == nil {
+				return fmt.Errorf("Object %d was not found in the object tree", current)
+			}
+			if node.Parent == nil {
+				return nil
+			}
+			current = node.Parent.ID
+		default:
+			return nil
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package prtgapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// treeHandler serves the object trees below objects, keyed on object ID, use "0" for the whole installation
+func treeHandler(t *testing.T, mux *http.ServeMux, trees map[string]string) {
+	mux.HandleFunc("/api/table.xml", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testAuthentication(t, r)
+		testParams(t, r, map[string]string{
+			"content": "sensortree",
+		})
+		w.Header().Add("Content-Type", "text/xml; charset=UTF-8")
+		w.WriteHeader(200)
+		fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?><prtg><version>19.4.53.1912</version><sensortree><nodes>%s</nodes></sensortree></prtg>`, trees[r.URL.Query().Get("id")])
+	})
+}
+
+func TestDevicesService_GetDependency(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	newPropertyStore(t, mux, map[string]map[string]string{
+		"1234": {"dependencytype": "1", "dependency": "2001", "depdelay": "60"},
+	})
+
+	ctx := context.Background()
+	got, err := client.Devices().GetDependency(ctx, 1234)
+	if err != nil {
+		t.Fatalf("Error while getting dependency: %v", err)
+	}
+	if want := (&Dependency{Type: DependencyObject, ObjectID: 2001, Delay: time.Minute}); !reflect.DeepEqual(got, want) {
+		t.Errorf("Got %+v, expected %+v", got, want)
+	}
+}
+
+func TestGroupsService_SetDependency(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	store := newPropertyStore(t, mux, map[string]map[string]string{
+		"2001": {"dependencytype": "1", "dependency": "3001"},
+		"3001": {"dependencytype": "0"},
+	})
+	treeHandler(t, mux, map[string]string{
+		"0": `<group id="0"><name>Root</name>` +
+			`<group id="1000"><name>Ingresses</name><device id="1234"><name>testdevice</name></device></group>` +
+			`<device id="3000"><name>backend</name><sensor id="2001"><name>HTTP</name></sensor><sensor id="3001"><name>Ping</name></sensor></device>` +
+			`</group>`,
+	})
+
+	ctx := context.Background()
+	err := client.Groups().SetDependency(ctx, 1000, Dependency{Type: DependencyObject, ObjectID: 2001, Delay: 30 * time.Second})
+	if err != nil {
+		t.Fatalf("Error while setting dependency: %v", err)
+	}
+	want := map[string]map[string]string{
+		"1000": {"dependencytype": "1", "dependency": "2001", "depdelay": "30"},
+	}
+	if written := store.written(); !reflect.DeepEqual(written, want) {
+		t.Errorf("Expected %v to be written, got %v", want, written)
+	}
+
+	err = client.Groups().SetDependency(ctx, 1000, Dependency{Type: DependencyMaster})
+	if err == nil {
+		t.Errorf("Expected an error when making a group a master object")
+	}
+}
+
+func TestSensorsService_SetDependencyCircular(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	store := newPropertyStore(t, mux, map[string]map[string]string{
+		"2001": {"dependencytype": "1", "dependency": "3001"},
+		"3001": {"dependencytype": "1", "dependency": "1234"},
+	})
+	treeHandler(t, mux, map[string]string{
+		"0": `<group id="0"><name>Root</name>` +
+			`<device id="1000"><name>testdevice</name><sensor id="1234"><name>Ping</name></sensor></device>` +
+			`<device id="3000"><name>backend</name><sensor id="2001"><name>HTTP</name></sensor><sensor id="3001"><name>Ping</name></sensor></device>` +
+			`</group>`,
+	})
+
+	ctx := context.Background()
+	err := client.Sensors().SetDependency(ctx, 1234, Dependency{Type: DependencyObject, ObjectID: 2001})
+	if !errors.Is(err, ErrCircularDependency) {
+		t.Errorf("Expected ErrCircularDependency, got %v", err)
+	}
+	if written := store.written(); len(written) > 0 {
+		t.Errorf("Expected nothing to be written, got %v", written)
+	}
+}
+
+func TestGroupsService_SetDependencyOnDescendant(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	store := newPropertyStore(t, mux, map[string]map[string]string{
+		"4001": {"dependencytype": "1", "dependency": "2345"},
+	})
+	treeHandler(t, mux, map[string]string{
+		"0": `<group id="0"><name>Root</name>` +
+			`<group id="1000"><name>Ingresses</name><device id="1234"><name>testdevice</name><sensor id="2345"><name>Ping</name></sensor></device></group>` +
+			`<device id="4001"><name>backend</name></device>` +
+			`</group>`,
+	})
+
+	ctx := context.Background()
+	// The group can't depend on a sensor of one of its own devices
+	err := client.Groups().SetDependency(ctx, 1000, Dependency{Type: DependencyObject, ObjectID: 2345})
+	if !errors.Is(err, ErrCircularDependency) {
+		t.Errorf("Expected ErrCircularDependency, got %v", err)
+	}
+
+	// Nor on an object outside of the group that depends on one of its sensors
+	err = client.Groups().SetDependency(ctx, 1000, Dependency{Type: DependencyObject, ObjectID: 4001})
+	if !errors.Is(err, ErrCircularDependency) {
+		t.Errorf("Expected ErrCircularDependency, got %v", err)
+	}
+
+	if written := store.written(); len(written) > 0 {
+		t.Errorf("Expected nothing to be written, got %v", written)
+	}
+}
+
+func TestDevicesService_SetDependencyCircularThroughParent(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	// Sensor 5001 and device 5000 use the dependency of group 6000, which depends on a sensor of device 1234
+	store := newPropertyStore(t, mux, map[string]map[string]string{
+		"5001": {"dependencytype": "0"},
+		"5000": {"dependencytype": "0"},
+		"6000": {"dependencytype": "1", "dependency": "2345"},
+	})
+	treeHandler(t, mux, map[string]string{
+		"0": `<group id="0"><name>Root</name>` +
+			`<group id="1000"><name>Ingresses</name><device id="1234"><name>testdevice</name><sensor id="2345"><name>Ping</name></sensor></device></group>` +
+			`<group id="6000"><name>Backends</name><device id="5000"><name>backend</name><sensor id="5001"><name>HTTP</name></sensor></device></group>` +
+			`</group>`,
+	})
+
+	ctx := context.Background()
+	err := client.Devices().SetDependency(ctx, 1234, Dependency{Type: DependencyObject, ObjectID: 5001})
+	if !errors.Is(err, ErrCircularDependency) {
+		t.Errorf("Expected ErrCircularDependency, got %v", err)
+	}
+	if written := store.written(); len(written) > 0 {
+		t.Errorf("Expected nothing to be written, got %v", written)
+	}
+}
@@ -2,10 +2,13 @@ package prtgapi
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/url"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // DevicesService handles communication with the device related methods of the PRTG API
@@ -16,14 +19,80 @@ type deviceList struct {
 }
 
 // Device represents a PRTG device
+//
+// Only the fields for the columns requested through DeviceListOptions.Columns are filled,
+// by default these are ID, Name and Host.
 type Device struct {
-	ID   int64  `json:"objid"`
-	Name string `json:"device"`
-	Host string
+	ID         int64  `json:"objid"`
+	Name       string `json:"device"`
+	Host       string
+	Status     Status   `json:"status_raw"`
+	StatusText string   `json:"status"`
+	Message    string   `json:"message_raw"`
+	Tags       []string `json:"-"`
+	Group      string   `json:"group"`
+	Probe      string   `json:"probe"`
+	ParentID   int64    `json:"parentid"`
+	Active     bool     `json:"-"`
+	Priority   int      `json:"priority_raw"`
+	Favorite   bool     `json:"-"`
+	Location   string   `json:"location_raw"`
+	Comments   string   `json:"comments"`
+
+	UpSensors               int `json:"upsens_raw"`
+	DownSensors             int `json:"downsens_raw"`
+	DownAcknowledgedSensors int `json:"downacksens_raw"`
+	PartialDownSensors      int `json:"partialdownsens_raw"`
+	WarningSensors          int `json:"warnsens_raw"`
+	PausedSensors           int `json:"pausedsens_raw"`
+	UnusualSensors          int `json:"unusualsens_raw"`
+	UndefinedSensors        int `json:"undefinedsens_raw"`
+	TotalSensors            int `json:"totalsens_raw"`
+}
+
+// UnmarshalJSON decodes a device row from a PRTG table, converting the tags
+// and the PRTG style booleans into their Go counterparts
+func (d *Device) UnmarshalJSON(data []byte) error {
+	type device Device
+	aux := struct {
+		*device
+		Tags     string `json:"tags"`
+		Active   int    `json:"active_raw"`
+		Favorite int    `json:"favorite_raw"`
+	}{
+		device: (*device)(d),
+	}
+
+	err := json.Unmarshal(data, &aux)
+	if err != nil {
+		return err
+	}
+
+	if aux.Tags != "" {
+		d.Tags = parseTags(aux.Tags)
+	}
+	d.Active = aux.Active != 0
+	d.Favorite = aux.Favorite != 0
+
+	return nil
+}
+
+// DefaultDeviceColumns are the columns that are fetched when no columns are given in DeviceListOptions
+var DefaultDeviceColumns = []string{"objid", "device", "host"}
+
+// AllDeviceColumns are all the columns that can be mapped onto a Device
+var AllDeviceColumns = []string{
+	"objid", "device", "host", "status", "message", "tags", "group", "probe", "parentid",
+	"active", "priority", "favorite", "location", "comments",
+	"upsens", "downsens", "downacksens", "partialdownsens", "warnsens",
+	"pausedsens", "unusualsens", "undefinedsens", "totalsens",
 }
 
 // DeviceListOptions can be used to filter devices when calling List or Get*
 //
+// Columns selects which columns are fetched from PRTG, when empty DefaultDeviceColumns is used.
+// Use AllDeviceColumns to fill every field of the Device. The objid column is always fetched.
+//
 // Currently it is possible to filter on
 // * ID (note, most of the times this refers to the ID of the parent)
 // * Tags
@@ -32,18 +101,65 @@ type Device struct {
 //		"objid": "12345"
 //	}
 type DeviceListOptions struct {
-	ID     int64
-	Tags   []string
-	Filter map[string]string
+	ID      int64
+	Tags    []string
+	Filter  map[string]string
+	Columns []string
 }
 
 const (
 	deviceListPath              = "/api/table.json"
+	addDevicePath               = "/api/adddevice2.htm"
 	devicePausePath             = "/api/pause.htm"
+	devicePauseForPath          = "/api/pauseobjectfor.htm"
+	discoverDevicePath          = "/api/discovernow.htm"
+	scanDevicePath              = "/api/scannow.htm"
 	duplicateDevicePath         = "/api/duplicateobject.htm"
+	deleteDevicePath            = "/api/deleteobject.htm"
+	moveDevicePath              = "/api/moveobjectnow.htm"
+	renameDevicePath            = "/api/rename.htm"
+	getDeviceObjectPropertyPath = "/api/getobjectproperty.htm"
 	setDeviceUpdatePropertyPath = "/api/setobjectproperty.htm"
 )
 
+// IPVersion selects the IP version PRTG uses to connect to a device
+type IPVersion int
+
+// The IP versions supported by PRTG
+const (
+	IPv4 IPVersion = 0
+	IPv6 IPVersion = 1
+)
+
+// DiscoveryMode selects how PRTG runs the auto-discovery on a new device
+type DiscoveryMode int
+
+// The auto-discovery modes supported by PRTG
+const (
+	// DiscoveryManual doesn't run an auto-discovery, sensors have to be added by hand
+	DiscoveryManual DiscoveryMode = 0
+	// DiscoveryDefault runs the standard auto-discovery
+	DiscoveryDefault DiscoveryMode = 1
+	// DiscoveryDetailed runs the detailed auto-discovery, which may create a lot of sensors
+	DiscoveryDetailed DiscoveryMode = 2
+	// DiscoveryTemplates runs the auto-discovery with the device templates in DeviceSpec.Templates
+	DiscoveryTemplates DiscoveryMode = 3
+)
+
+// DeviceSpec describes a device that is created with Create
+//
+// Name and Host are required. Templates are the file names of the device templates
+// (e.g. "ping.odt") and are only used when DiscoveryMode is DiscoveryTemplates.
+type DeviceSpec struct {
+	Name          string
+	Host          string
+	IPVersion     IPVersion
+	Tags          []string
+	Icon          string
+	DiscoveryMode DiscoveryMode
+	Templates     []string
+}
+
 // NewDevicesService returns a new DevicesService for a given client
 func NewDevicesService(client *Client) *DevicesService {
 	return &DevicesService{
@@ -51,11 +167,66 @@ func NewDevicesService(client *Client) *DevicesService {
 	}
 }
 
+// DuplicateOptions configures the steps Duplicate runs on the new device
+//
+// Tags are added to the tags the new device copied from the template.
+// Properties are set with UpdateProperty. When Unpause is set the new device is unpaused,
+// PRTG pauses duplicated devices by default. When WaitForSensors is set, Duplicate waits
+// until the new device has as many sensors as the template, using WaitOptions for the polling.
+type DuplicateOptions struct {
+	Tags           []string
+	Properties     map[string]string
+	Unpause        bool
+	WaitForSensors bool
+	WaitOptions    PollOptions
+}
+
+// DuplicateError is returned by Duplicate when setting up the new device failed.
+//
+// Duplicate deletes the new device in that case, RollbackErr holds the error
+// of the deletion when that failed as well and the device was left behind.
+type DuplicateError struct {
+	DeviceID    int64
+	Err         error
+	RollbackErr error
+}
+
+func (e *DuplicateError) Error() string {
+	if e.RollbackErr != nil {
+		return fmt.Sprintf("Error while setting up duplicated device %d: %v. Deleting the device failed as well: %v", e.DeviceID, e.Err, e.RollbackErr)
+	}
+	return fmt.Sprintf("Error while setting up duplicated device %d, the device was deleted: %v", e.DeviceID, e.Err)
+}
+
+// Unwrap returns the error that caused the rollback
+func (e *DuplicateError) Unwrap() error {
+	return e.Err
+}
+
 // Duplicate duplicates the device identified by templateDeviceID into a group
 // identified by parentGroupID.
 //
-// Tags can be left empty if you don't want your new device to be tagged.
-func (d *DevicesService) Duplicate(ctx context.Context, templateDeviceID int64, parentGroupID int64, name string, hostname string, tags []string) (*Device, error) {
+// When any step after the duplication fails, the new device is deleted again
+// and a *DuplicateError is returned.
+func (d *DevicesService) Duplicate(ctx context.Context, templateDeviceID int64, parentGroupID int64, name string, hostname string, options DuplicateOptions) (*Device, error) {
+	if err := validateTags(options.Tags); err != nil {
+		return nil, err
+	}
+
+	templateSensors := 0
+	if options.WaitForSensors {
+		template, err := d.GetByID(ctx, templateDeviceID, DeviceListOptions{
+			Columns: []string{"objid", "totalsens"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if template == nil {
+			return nil, fmt.Errorf("Template device %d was not found", templateDeviceID)
+		}
+		templateSensors = template.TotalSensors
+	}
+
 	v := url.Values{}
 	v.Set("id", strconv.FormatInt(templateDeviceID, 10))
 	v.Set("targetid", strconv.FormatInt(parentGroupID, 10))
@@ -69,23 +240,74 @@ func (d *DevicesService) Duplicate(ctx context.Context, templateDeviceID int64,
 	}
 
 	// Get the deviceID from the redirect response
-	newDeviceURL, err := url.Parse(res.Location)
+	newDeviceID, err := res.objectID()
 	if err != nil {
 		return nil, err
 	}
 
-	newDeviceID, err := strconv.ParseInt(newDeviceURL.Query().Get("id"), 10, 64)
+	newDevice, err := d.setupDuplicate(ctx, newDeviceID, templateSensors, options)
 	if err != nil {
-		return nil, err
+		rollbackCtx, cancel := context.WithTimeout(context.Background(), cleanupTimeout)
+		defer cancel()
+
+		return nil, &DuplicateError{
+			DeviceID:    newDeviceID,
+			Err:         err,
+			RollbackErr: d.Delete(rollbackCtx, newDeviceID),
+		}
 	}
 
-	newDevice, err := d.GetByID(ctx, newDeviceID, DeviceListOptions{})
+	return newDevice, nil
+}
+
+func (d *DevicesService) setupDuplicate(ctx context.Context, id int64, templateSensors int, options DuplicateOptions) (*Device, error) {
+	newDevice, err := d.GetByID(ctx, id, DeviceListOptions{})
 	if err != nil {
 		return nil, err
 	}
+	if newDevice == nil {
+		return nil, fmt.Errorf("Device %d was not found after duplicating it", id)
+	}
+
+	if len(options.Tags) > 0 {
+		newDevice.Tags, err = d.AddTags(ctx, id, options.Tags, TagUpdateOptions{})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	names := make([]string, 0, len(options.Properties))
+	for name := range options.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		err = d.UpdateProperty(ctx, id, name, options.Properties[name])
+		if err != nil {
+			return nil, err
+		}
+	}
 
-	if len(tags) > 0 {
-		err = d.UpdateProperty(ctx, newDevice.ID, "tags", strings.Join(tags, ","))
+	if options.Unpause {
+		err = d.Unpause(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if options.WaitForSensors {
+		err = poll(ctx, options.WaitOptions, func(ctx context.Context) (bool, error) {
+			device, err := d.GetByID(ctx, id, DeviceListOptions{
+				Columns: []string{"objid", "totalsens"},
+			})
+			if err != nil {
+				return false, err
+			}
+			if device == nil {
+				return false, fmt.Errorf("Device %d was not found while waiting for its sensors", id)
+			}
+			return device.TotalSensors >= templateSensors, nil
+		})
 		if err != nil {
 			return nil, err
 		}
@@ -94,11 +316,53 @@ func (d *DevicesService) Duplicate(ctx context.Context, templateDeviceID int64,
 	return newDevice, nil
 }
 
+// Create creates a new device in the group identified by parentGroupID
+func (d *DevicesService) Create(ctx context.Context, parentGroupID int64, spec DeviceSpec) (*Device, error) {
+	if spec.Name == "" || spec.Host == "" {
+		return nil, fmt.Errorf("A name and host are required to create a device")
+	}
+	if spec.DiscoveryMode == DiscoveryTemplates && len(spec.Templates) == 0 {
+		return nil, fmt.Errorf("Auto-discovery with templates requires at least one device template")
+	}
+
+	v := url.Values{}
+	v.Set("id", strconv.FormatInt(parentGroupID, 10))
+	v.Set("name_", spec.Name)
+	v.Set("host_", spec.Host)
+	v.Set("ipversion_", strconv.Itoa(int(spec.IPVersion)))
+	v.Set("discoverytype_", strconv.Itoa(int(spec.DiscoveryMode)))
+	if len(spec.Tags) > 0 {
+		v.Set("tags_", strings.Join(spec.Tags, ","))
+	}
+	if spec.Icon != "" {
+		v.Set("deviceicon_", spec.Icon)
+	}
+	if spec.DiscoveryMode == DiscoveryTemplates {
+		v.Set("devicetemplate_", "1")
+		for _, template := range spec.Templates {
+			v.Add("devicetemplate__check", template)
+		}
+	}
+
+	res := &redirectResponse{}
+	err := d.client.do(ctx, addDevicePath, v, res)
+	if err != nil {
+		return nil, err
+	}
+
+	newDeviceID, err := res.objectID()
+	if err != nil {
+		return nil, err
+	}
+
+	return d.GetByID(ctx, newDeviceID, DeviceListOptions{})
+}
+
 // List returns a list of devices that match the given options
 func (d *DevicesService) List(ctx context.Context, options DeviceListOptions) ([]*Device, error) {
 	v := url.Values{}
 	v.Set("content", "devices")
-	v.Set("columns", "objid,device,host")
+	v.Set("columns", columnsParam(options.Columns, DefaultDeviceColumns))
 	if options.ID != 0 {
 		v.Set("id", strconv.FormatInt(options.ID, 10))
 	}
@@ -140,13 +404,141 @@ func (d *DevicesService) GetByID(ctx context.Context, id int64, options DeviceLi
 	return d.get(ctx, options)
 }
 
+// GetProperty returns the current value of a property of a device
+func (d *DevicesService) GetProperty(ctx context.Context, id int64, name string) (string, error) {
+	v := url.Values{}
+	v.Set("id", strconv.FormatInt(id, 10))
+	v.Set("name", name)
+
+	var propertyResult struct {
+		Value string `xml:"result"`
+	}
+
+	err := d.client.do(ctx, getDeviceObjectPropertyPath, v, &propertyResult)
+	if err != nil {
+		return "", err
+	}
+
+	return propertyResult.Value, nil
+}
+
 // UpdateProperty updates a property on a device
 func (d *DevicesService) UpdateProperty(ctx context.Context, id int64, name string, value string) error {
 	v := url.Values{}
 	v.Set("id", strconv.FormatInt(id, 10))
 	v.Set("name", name)
 	v.Set("value", value)
-	return d.client.do(ctx, setSensorObjectPropertyPath, v, nil)
+	return d.client.do(ctx, setDeviceUpdatePropertyPath, v, nil)
+}
+
+// Move moves the device into the group identified by targetGroupID
+func (d *DevicesService) Move(ctx context.Context, id int64, targetGroupID int64) error {
+	v := url.Values{}
+	v.Set("id", strconv.FormatInt(id, 10))
+	v.Set("targetid", strconv.FormatInt(targetGroupID, 10))
+	return d.client.do(ctx, moveDevicePath, v, nil)
+}
+
+// Rename changes the name of the device
+func (d *DevicesService) Rename(ctx context.Context, id int64, name string) error {
+	v := url.Values{}
+	v.Set("id", strconv.FormatInt(id, 10))
+	v.Set("value", name)
+	return d.client.do(ctx, renameDevicePath, v, nil)
+}
+
+// Discover starts an auto-discovery on the device.
+//
+// When templates are given (e.g. "ping.odt"), the auto-discovery only uses those device templates,
+// otherwise the templates configured on the device are used.
+// The auto-discovery runs in the background, use WaitForDiscovery to wait for it to finish.
+func (d *DevicesService) Discover(ctx context.Context, id int64, templates ...string) error {
+	v := url.Values{}
+	v.Set("id", strconv.FormatInt(id, 10))
+	if len(templates) > 0 {
+		quoted := make([]string, len(templates))
+		for i, template := range templates {
+			quoted[i] = strconv.Quote(template)
+		}
+		v.Set("template", strings.Join(quoted, ","))
+	}
+	return d.client.do(ctx, discoverDevicePath, v, nil)
+}
+
+// discoveryStartPolls is the number of polls after which WaitForDiscovery stops waiting for
+// the auto-discovery to show up and accepts a stable number of sensors as finished
+const discoveryStartPolls = 5
+
+// WaitForDiscovery polls the device until the auto-discovery has finished or the context is done.
+//
+// The auto-discovery is considered finished when PRTG no longer reports it in the status
+// or message of the device and the number of sensors on the device didn't change between two polls.
+// PRTG may take a moment to start a discovery requested with Discover, so this only happens after
+// the discovery has been seen in progress, the number of sensors has grown since the first poll,
+// or after discoveryStartPolls polls. The last covers discoveries that finish between two polls
+// without adding sensors, e.g. when the device already has all its sensors.
+// The device is returned with its status, message and sensor counts filled.
+func (d *DevicesService) WaitForDiscovery(ctx context.Context, id int64, options PollOptions) (*Device, error) {
+	var device *Device
+	initialSensors, previousSensors := -1, -1
+	started := false
+	polls := 0
+
+	err := poll(ctx, options, func(ctx context.Context) (bool, error) {
+		var err error
+		device, err = d.GetByID(ctx, id, DeviceListOptions{
+			Columns: []string{"objid", "device", "host", "status", "message", "totalsens"},
+		})
+		if err != nil {
+			return false, err
+		}
+		if device == nil {
+			return false, fmt.Errorf("Device %d was not found while waiting for the auto-discovery", id)
+		}
+
+		polls++
+		if initialSensors < 0 {
+			initialSensors = device.TotalSensors
+		}
+
+		if isDiscovering(device.StatusText) || isDiscovering(device.Message) {
+			started = true
+			previousSensors = -1
+			return false, nil
+		}
+		if device.TotalSensors > initialSensors || polls >= discoveryStartPolls {
+			started = true
+		}
+
+		stable := started && device.TotalSensors == previousSensors
+		previousSensors = device.TotalSensors
+		return stable, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return device, nil
+}
+
+func isDiscovering(text string) bool {
+	text = strings.ToLower(text)
+	return strings.Contains(text, "discovery") || strings.Contains(text, "discovering")
+}
+
+// ScanNow requests PRTG to scan all sensors of the device immediately
+func (d *DevicesService) ScanNow(ctx context.Context, id int64) error {
+	v := url.Values{}
+	v.Set("id", strconv.FormatInt(id, 10))
+	return d.client.do(ctx, scanDevicePath, v, nil)
+}
+
+// Delete deletes the device and all of its sensors
+func (d *DevicesService) Delete(ctx context.Context, id int64) error {
+	v := url.Values{}
+	v.Set("id", strconv.FormatInt(id, 10))
+	v.Set("approve", "1")
+	return d.client.do(ctx, deleteDevicePath, v, nil)
 }
 
 // Pause pauses the device indefinitely
@@ -158,6 +550,21 @@ func (d *DevicesService) Pause(ctx context.Context, id int64, message string) er
 	return d.client.do(ctx, devicePausePath, v, nil)
 }
 
+// PauseFor pauses the device for the given duration, after which PRTG resumes it automatically.
+// The duration is rounded up to whole minutes.
+func (d *DevicesService) PauseFor(ctx context.Context, id int64, duration time.Duration, message string) error {
+	minutes, err := durationMinutes(duration)
+	if err != nil {
+		return err
+	}
+
+	v := url.Values{}
+	v.Set("id", strconv.FormatInt(id, 10))
+	v.Set("duration", strconv.FormatInt(minutes, 10))
+	v.Set("pausemsg", message)
+	return d.client.do(ctx, devicePauseForPath, v, nil)
+}
+
 // Unpause unpauses the device
 func (d *DevicesService) Unpause(ctx context.Context, id int64) error {
 	v := url.Values{}
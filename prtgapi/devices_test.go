@@ -2,9 +2,11 @@ package prtgapi
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"reflect"
 	"testing"
+	"time"
 )
 
 func testDevicesService_List(t *testing.T) {
@@ -60,28 +62,319 @@ func TestDevicesService_Duplicate(t *testing.T) {
 		w.Write(deviceListJSON)
 	})
 
-	mux.HandleFunc("/api/setobjectproperty.htm", func(w http.ResponseWriter, r *http.Request) {
+	store := newPropertyStore(t, mux, map[string]map[string]string{
+		"1234": {"tags": "template mytag"},
+	})
+
+	ctx := context.Background()
+	got, err := client.Devices().Duplicate(ctx, 123, 321, "testdevice", "testdevice.example.com", DuplicateOptions{
+		Tags: []string{"mytag", "another-tag"},
+	})
+	if err != nil {
+		t.Errorf("Error while duplicating devices: %v", err)
+	}
+	want := *wantDevice
+	want.Tags = []string{"template", "mytag", "another-tag"}
+	if !reflect.DeepEqual(got, &want) {
+		t.Errorf("Got %v, expected %v", got, want)
+	}
+	if tags := store.written()["1234"]["tags"]; tags != "template,mytag,another-tag" {
+		t.Errorf("Expected tags template,mytag,another-tag to be written, got %q", tags)
+	}
+}
+
+func TestDevicesService_Create(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/api/adddevice2.htm", func(w http.ResponseWriter, r *http.Request) {
 		testMethod(t, r, "GET")
+		testAuthentication(t, r)
 		testParams(t, r, map[string]string{
-			"id":    "1234",
-			"name":  "tags",
-			"value": "mytag,another-tag",
+			"id":                    "321",
+			"name_":                 "testdevice",
+			"host_":                 "testdevice.example.com",
+			"ipversion_":            "1",
+			"tags_":                 "mytag,another-tag",
+			"deviceicon_":           "a_server_1.png",
+			"discoverytype_":        "3",
+			"devicetemplate_":       "1",
+			"devicetemplate__check": "ping.odt",
 		})
 		w.Header().Add("Content-Type", "text/html; charset=UTF-8")
+		w.Header().Add("Location", "/device.htm?id=1234")
+		w.WriteHeader(302)
+	})
+
+	mux.HandleFunc("/api/table.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testParams(t, r, map[string]string{
+			"filter_objid": "1234",
+		})
+		w.Header().Add("Content-Type", "application/json; charset=UTF-8")
 		w.WriteHeader(200)
-		w.Write([]byte(`<HTML><BODY class="no-content"><B class="no-content">OK</B></BODY></HTML>`))
+		w.Write(deviceListJSON)
 	})
 
 	ctx := context.Background()
-	got, err := client.Devices().Duplicate(ctx, 123, 321, "testdevice", "testdevice.example.com", []string{"mytag", "another-tag"})
+	got, err := client.Devices().Create(ctx, 321, DeviceSpec{
+		Name:          "testdevice",
+		Host:          "testdevice.example.com",
+		IPVersion:     IPv6,
+		Tags:          []string{"mytag", "another-tag"},
+		Icon:          "a_server_1.png",
+		DiscoveryMode: DiscoveryTemplates,
+		Templates:     []string{"ping.odt"},
+	})
 	if err != nil {
-		t.Errorf("Error while duplicating devices: %v", err)
+		t.Errorf("Error while creating device: %v", err)
 	}
 	if want := wantDevice; !reflect.DeepEqual(got, want) {
 		t.Errorf("Got %v, expected %v", got, want)
 	}
 }
 
+func TestDevicesService_Discover(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/api/discovernow.htm", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testAuthentication(t, r)
+		testParams(t, r, map[string]string{
+			"id":       "1234",
+			"template": `"ping.odt","http.odt"`,
+		})
+		w.Header().Add("Content-Type", "text/html; charset=UTF-8")
+		w.WriteHeader(200)
+		w.Write([]byte(`<HTML><BODY class="no-content"><B class="no-content">OK</B></BODY></HTML>`))
+	})
+
+	ctx := context.Background()
+	err := client.Devices().Discover(ctx, 1234, "ping.odt", "http.odt")
+	if err != nil {
+		t.Errorf("Error while starting auto-discovery: %v", err)
+	}
+}
+
+func TestDevicesService_WaitForDiscovery(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	responses := []struct {
+		message string
+		sensors int
+	}{
+		{"Auto-Discovery 30%", 1},
+		{"OK", 3},
+		{"OK", 3},
+	}
+	calls := 0
+
+	mux.HandleFunc("/api/table.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testParams(t, r, map[string]string{
+			"filter_objid": "1234",
+		})
+		response := responses[len(responses)-1]
+		if calls < len(responses) {
+			response = responses[calls]
+		}
+		calls++
+		w.Header().Add("Content-Type", "application/json; charset=UTF-8")
+		w.WriteHeader(200)
+		fmt.Fprintf(w, `{"devices": [{"objid": 1234, "device": "testdevice", "message_raw": %q, "totalsens_raw": %d}]}`, response.message, response.sensors)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	got, err := client.Devices().WaitForDiscovery(ctx, 1234, PollOptions{Interval: time.Millisecond})
+	if err != nil {
+		t.Fatalf("Error while waiting for auto-discovery: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("Expected 3 polls, got %d", calls)
+	}
+	if got.TotalSensors != 3 {
+		t.Errorf("Expected 3 sensors, got %d", got.TotalSensors)
+	}
+}
+
+func TestDevicesService_WaitForDiscoveryNotStarted(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	// PRTG hasn't queued the discovery yet on the first polls
+	responses := []struct {
+		message string
+		sensors int
+	}{
+		{"OK", 1},
+		{"OK", 1},
+		{"Auto-Discovery 10%", 1},
+		{"OK", 4},
+		{"OK", 4},
+	}
+	calls := 0
+
+	mux.HandleFunc("/api/table.json", func(w http.ResponseWriter, r *http.Request) {
+		response := responses[len(responses)-1]
+		if calls < len(responses) {
+			response = responses[calls]
+		}
+		calls++
+		w.Header().Add("Content-Type", "application/json; charset=UTF-8")
+		w.WriteHeader(200)
+		fmt.Fprintf(w, `{"devices": [{"objid": 1234, "device": "testdevice", "message_raw": %q, "totalsens_raw": %d}]}`, response.message, response.sensors)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	got, err := client.Devices().WaitForDiscovery(ctx, 1234, PollOptions{Interval: time.Millisecond})
+	if err != nil {
+		t.Fatalf("Error while waiting for auto-discovery: %v", err)
+	}
+	if calls != 5 {
+		t.Errorf("Expected 5 polls, got %d", calls)
+	}
+	if got.TotalSensors != 4 {
+		t.Errorf("Expected 4 sensors, got %d", got.TotalSensors)
+	}
+}
+
+func TestDevicesService_WaitForDiscoveryWithoutNewSensors(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	// The discovery finished before the first poll and the device already had all its sensors
+	calls := 0
+	mux.HandleFunc("/api/table.json", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Add("Content-Type", "application/json; charset=UTF-8")
+		w.WriteHeader(200)
+		w.Write([]byte(`{"devices": [{"objid": 1234, "device": "testdevice", "message_raw": "OK", "totalsens_raw": 4}]}`))
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	got, err := client.Devices().WaitForDiscovery(ctx, 1234, PollOptions{Interval: time.Millisecond})
+	if err != nil {
+		t.Fatalf("Error while waiting for auto-discovery: %v", err)
+	}
+	if calls != discoveryStartPolls {
+		t.Errorf("Expected %d polls, got %d", discoveryStartPolls, calls)
+	}
+	if got.TotalSensors != 4 {
+		t.Errorf("Expected 4 sensors, got %d", got.TotalSensors)
+	}
+}
+
+func TestDevicesService_ScanNow(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/api/scannow.htm", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testAuthentication(t, r)
+		testParams(t, r, map[string]string{
+			"id": "1234",
+		})
+		w.Header().Add("Content-Type", "text/html; charset=UTF-8")
+		w.WriteHeader(200)
+		w.Write([]byte(`<HTML><BODY class="no-content"><B class="no-content">OK</B></BODY></HTML>`))
+	})
+
+	ctx := context.Background()
+	err := client.Devices().ScanNow(ctx, 1234)
+	if err != nil {
+		t.Errorf("Error while scanning device: %v", err)
+	}
+}
+
+func TestDevicesService_PauseFor(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/api/pauseobjectfor.htm", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testAuthentication(t, r)
+		testParams(t, r, map[string]string{
+			"id":       "1234",
+			"duration": "90",
+			"pausemsg": "Deploying",
+		})
+		w.Header().Add("Content-Type", "text/html; charset=UTF-8")
+		w.WriteHeader(200)
+		w.Write([]byte(`<HTML><BODY class="no-content"><B class="no-content">OK</B></BODY></HTML>`))
+	})
+
+	ctx := context.Background()
+	err := client.Devices().PauseFor(ctx, 1234, 89*time.Minute+30*time.Second, "Deploying")
+	if err != nil {
+		t.Errorf("Error while pausing device: %v", err)
+	}
+
+	err = client.Devices().PauseFor(ctx, 1234, 0, "Deploying")
+	if err == nil {
+		t.Errorf("Expected an error when pausing for a zero duration")
+	}
+}
+
+func TestDevicesService_DuplicateRollback(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/api/duplicateobject.htm", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Location", "/device.htm?id=1234")
+		w.WriteHeader(302)
+	})
+
+	mux.HandleFunc("/api/table.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json; charset=UTF-8")
+		w.WriteHeader(200)
+		w.Write(deviceListJSON)
+	})
+
+	mux.HandleFunc("/api/getobjectproperty.htm", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(500)
+	})
+
+	deleted := false
+	mux.HandleFunc("/api/deleteobject.htm", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testAuthentication(t, r)
+		testParams(t, r, map[string]string{
+			"id":      "1234",
+			"approve": "1",
+		})
+		deleted = true
+		w.Header().Add("Content-Type", "text/html; charset=UTF-8")
+		w.WriteHeader(200)
+		w.Write([]byte(`<HTML><BODY class="no-content"><B class="no-content">OK</B></BODY></HTML>`))
+	})
+
+	ctx := context.Background()
+	got, err := client.Devices().Duplicate(ctx, 123, 321, "testdevice", "testdevice.example.com", DuplicateOptions{
+		Tags: []string{"mytag"},
+	})
+	if got != nil {
+		t.Errorf("Expected no device to be returned, got %v", got)
+	}
+	duplicateErr, ok := err.(*DuplicateError)
+	if !ok {
+		t.Fatalf("Expected a DuplicateError, got %v", err)
+	}
+	if duplicateErr.DeviceID != 1234 || duplicateErr.Err == nil || duplicateErr.RollbackErr != nil {
+		t.Errorf("Unexpected DuplicateError %+v", duplicateErr)
+	}
+	if !deleted {
+		t.Errorf("Expected the duplicated device to be deleted")
+	}
+}
+
 func TestDevicesService_GetByID(t *testing.T) {
 	client, mux, _, teardown := setup()
 	defer teardown()
@@ -132,6 +425,98 @@ func TestDevicesService_Get(t *testing.T) {
 	}
 }
 
+func TestDevicesService_ListColumns(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/api/table.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testParams(t, r, map[string]string{
+			"content": "devices",
+			"columns": "objid,status,tags,active,priority,favorite,upsens,totalsens",
+		})
+		w.Header().Add("Content-Type", "application/json; charset=UTF-8")
+		w.WriteHeader(200)
+		w.Write(deviceListRichJSON)
+	})
+
+	ctx := context.Background()
+	got, err := client.Devices().List(ctx, DeviceListOptions{
+		Columns: []string{"status", "tags", "active", "priority", "favorite", "upsens", "totalsens"},
+	})
+	if err != nil {
+		t.Errorf("Error while getting devices: %v", err)
+	}
+	want := []*Device{
+		&Device{
+			ID:           1234,
+			Status:       StatusUp,
+			StatusText:   "Up",
+			Tags:         []string{"k8s-ingress", "k8s-ingress-id-abc"},
+			Active:       true,
+			Priority:     4,
+			Favorite:     true,
+			UpSensors:    3,
+			TotalSensors: 4,
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Got %d devices, expected %d", len(got), len(want))
+		for _, device := range got {
+			t.Errorf("Got %+v", *device)
+		}
+		for _, device := range want {
+			t.Errorf("Expected %+v", *device)
+		}
+	}
+}
+
+func TestDevicesService_Move(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/api/moveobjectnow.htm", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testAuthentication(t, r)
+		testParams(t, r, map[string]string{
+			"id":       "1234",
+			"targetid": "4321",
+		})
+		w.Header().Add("Content-Type", "text/html; charset=UTF-8")
+		w.WriteHeader(200)
+		w.Write([]byte(`<HTML><BODY class="no-content"><B class="no-content">OK</B></BODY></HTML>`))
+	})
+
+	ctx := context.Background()
+	err := client.Devices().Move(ctx, 1234, 4321)
+	if err != nil {
+		t.Errorf("Error while moving device: %v", err)
+	}
+}
+
+func TestDevicesService_Rename(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/api/rename.htm", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testAuthentication(t, r)
+		testParams(t, r, map[string]string{
+			"id":    "1234",
+			"value": "renamed-device",
+		})
+		w.Header().Add("Content-Type", "text/html; charset=UTF-8")
+		w.WriteHeader(200)
+		w.Write([]byte(`<HTML><BODY class="no-content"><B class="no-content">OK</B></BODY></HTML>`))
+	})
+
+	ctx := context.Background()
+	err := client.Devices().Rename(ctx, 1234, "renamed-device")
+	if err != nil {
+		t.Errorf("Error while renaming device: %v", err)
+	}
+}
+
 var wantDevice = &Device{
 	ID:   1234,
 	Name: "testdevice",
@@ -175,3 +560,26 @@ var devicesListJSON = []byte(`{
 		}
 	]
 }`)
+
+var deviceListRichJSON = []byte(`{
+	"prtg-version": "19.4.53.1912",
+	"treesize": 1,
+	"devices": [
+		{
+			"objid": 1234,
+			"status": "Up",
+			"status_raw": 3,
+			"tags": "k8s-ingress k8s-ingress-id-abc",
+			"active": true,
+			"active_raw": -1,
+			"priority": "****",
+			"priority_raw": 4,
+			"favorite": "",
+			"favorite_raw": 1,
+			"upsens": "3",
+			"upsens_raw": 3,
+			"totalsens": "4",
+			"totalsens_raw": 4
+		}
+	]
+}`)
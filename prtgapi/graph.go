@@ -0,0 +1,154 @@
+package prtgapi
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	graphPNGPath = "/chart.png"
+	graphSVGPath = "/chart.svg"
+)
+
+// GraphID selects one of the graphs PRTG shows for a sensor
+type GraphID int
+
+// The graphs PRTG shows for every sensor
+const (
+	GraphLive    GraphID = 0
+	Graph2Days   GraphID = 1
+	Graph30Days  GraphID = 2
+	Graph365Days GraphID = 3
+)
+
+// GraphFormat is the image format of a graph
+type GraphFormat string
+
+// The image formats PRTG can render graphs in
+const (
+	GraphPNG GraphFormat = "png"
+	GraphSVG GraphFormat = "svg"
+)
+
+// GraphOptions configures the graph returned by Graph
+//
+// When From and To are set, a graph of that period is rendered and GraphID is ignored.
+// The period is sent in the timezone of the PRTG core server, see Client.Location.
+// Width and Height are in pixels, PRTG picks a default size when they are left 0.
+// Theme is passed as the graphstyling parameter, e.g. "baseFontSize='12' showLegend='1'".
+// Format defaults to PNG.
+type GraphOptions struct {
+	GraphID GraphID
+	Width   int
+	Height  int
+	From    time.Time
+	To      time.Time
+	Theme   string
+	Format  GraphFormat
+}
+
+// Graph returns a graph of a sensor as an image. The caller must close the returned reader.
+//
+// The response is checked to really be an image, as PRTG answers with its login page
+// instead of an error when the credentials are not accepted.
+func (s *SensorsService) Graph(ctx context.Context, id int64, options GraphOptions) (io.ReadCloser, error) {
+	path := graphPNGPath
+	switch options.Format {
+	case "", GraphPNG:
+	case GraphSVG:
+		path = graphSVGPath
+	default:
+		return nil, fmt.Errorf("Unknown graph format %q", options.Format)
+	}
+
+	v := url.Values{}
+	v.Set("id", strconv.FormatInt(id, 10))
+	if !options.From.IsZero() || !options.To.IsZero() {
+		if options.From.IsZero() || options.To.IsZero() || !options.From.Before(options.To) {
+			return nil, fmt.Errorf("A graph period needs a start before its end, got %s to %s", options.From, options.To)
+		}
+		v.Set("graphid", "-1")
+		v.Set("sdate", s.client.formatTime(options.From))
+		v.Set("edate", s.client.formatTime(options.To))
+	} else {
+		v.Set("graphid", strconv.Itoa(int(options.GraphID)))
+	}
+	if options.Width > 0 {
+		v.Set("width", strconv.Itoa(options.Width))
+	}
+	if options.Height > 0 {
+		v.Set("height", strconv.Itoa(options.Height))
+	}
+	if options.Theme != "" {
+		v.Set("graphstyling", options.Theme)
+	}
+
+	res, err := s.client.request(ctx, path, v)
+	if err != nil {
+		return nil, err
+	}
+
+	image, err := checkImage(res)
+	if err != nil {
+		res.Body.Close()
+		return nil, fmt.Errorf("Error while getting graph of sensor %d: %v", id, err)
+	}
+
+	return image, nil
+}
+
+// checkImage makes sure a response holds an image and returns its body.
+// The start of the body is sniffed, so the returned reader replays it.
+func checkImage(res *http.Response) (io.ReadCloser, error) {
+	// PRTG redirects to the login page when the credentials are not accepted
+	if res.StatusCode == http.StatusFound {
+		return nil, fmt.Errorf("Error while authenticating to PRTG")
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Got a non-200 response from PRTG. Status %d", res.StatusCode)
+	}
+
+	mediatype, _, err := mime.ParseMediaType(res.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, fmt.Errorf("Invalid content type %q: %v", res.Header.Get("Content-Type"), err)
+	}
+	if !strings.HasPrefix(mediatype, "image/") {
+		return nil, fmt.Errorf("Expected an image, got %s", mediatype)
+	}
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(res.Body, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return nil, err
+	}
+	head = head[:n]
+
+	// SVG images are not recognized by DetectContentType, they are text based.
+	// For those it is only checked that the content is not an HTML page.
+	detected := http.DetectContentType(head)
+	if mediatype == "image/svg+xml" {
+		if strings.HasPrefix(detected, "text/html") {
+			return nil, fmt.Errorf("Expected an SVG image, got an HTML page")
+		}
+	} else if !strings.HasPrefix(detected, "image/") {
+		return nil, fmt.Errorf("Expected an image, got content that looks like %s", detected)
+	}
+
+	return &imageReader{
+		Reader: io.MultiReader(bytes.NewReader(head), res.Body),
+		Closer: res.Body,
+	}, nil
+}
+
+type imageReader struct {
+	io.Reader
+	io.Closer
+}
@@ -0,0 +1,113 @@
+package prtgapi
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+var pngImage = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR\x00\x00\x01\x90\x00\x00\x00\xc8")
+
+func TestSensorsService_Graph(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+	client.Location = time.FixedZone("CEST", 2*60*60)
+
+	mux.HandleFunc("/chart.png", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testAuthentication(t, r)
+		testParams(t, r, map[string]string{
+			"id":      "2345",
+			"graphid": "-1",
+			"sdate":   "2020-06-01-02-00-00",
+			"edate":   "2020-06-02-02-00-00",
+			"width":   "400",
+			"height":  "200",
+		})
+		w.Header().Add("Content-Type", "image/png")
+		w.WriteHeader(200)
+		w.Write(pngImage)
+	})
+
+	ctx := context.Background()
+	image, err := client.Sensors().Graph(ctx, 2345, GraphOptions{
+		Width:  400,
+		Height: 200,
+		From:   time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC),
+		To:     time.Date(2020, 6, 2, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("Error while getting graph: %v", err)
+	}
+	defer image.Close()
+
+	got, err := ioutil.ReadAll(image)
+	if err != nil {
+		t.Fatalf("Error while reading graph: %v", err)
+	}
+	if !bytes.Equal(got, pngImage) {
+		t.Errorf("Expected the complete image, got %q", got)
+	}
+}
+
+func TestSensorsService_Graph_SVG(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/chart.svg", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testParams(t, r, map[string]string{
+			"id":           "2345",
+			"graphid":      "2",
+			"graphstyling": "showLegend='1'",
+		})
+		w.Header().Add("Content-Type", "image/svg+xml")
+		w.WriteHeader(200)
+		w.Write([]byte(`<?xml version="1.0"?><svg xmlns="http://www.w3.org/2000/svg"></svg>`))
+	})
+
+	ctx := context.Background()
+	image, err := client.Sensors().Graph(ctx, 2345, GraphOptions{GraphID: Graph30Days, Theme: "showLegend='1'", Format: GraphSVG})
+	if err != nil {
+		t.Fatalf("Error while getting graph: %v", err)
+	}
+	image.Close()
+}
+
+func TestSensorsService_Graph_LoginPage(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/chart.png", func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("graphid") {
+		case "0":
+			w.Header().Add("Location", "/index.htm?loginurl=%2Fchart.png")
+			w.WriteHeader(302)
+		case "1":
+			w.Header().Add("Content-Type", "text/html; charset=UTF-8")
+			w.WriteHeader(200)
+			w.Write([]byte(`<!doctype html><html><body>Login</body></html>`))
+		default:
+			w.Header().Add("Content-Type", "image/png")
+			w.WriteHeader(200)
+			w.Write([]byte(`<!doctype html><html><body>Login</body></html>`))
+		}
+	})
+
+	ctx := context.Background()
+	for _, graphID := range []GraphID{GraphLive, Graph2Days, Graph365Days} {
+		image, err := client.Sensors().Graph(ctx, 2345, GraphOptions{GraphID: graphID})
+		if err == nil {
+			image.Close()
+			t.Errorf("Expected an error for graph %d", graphID)
+			continue
+		}
+		if graphID == GraphLive && !strings.Contains(err.Error(), "authenticating") {
+			t.Errorf("Expected an authentication error for a redirect, got %v", err)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package prtgapi
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+)
+
+// GroupsService handles communication with the group related methods of the PRTG API
+type GroupsService service
+
+const (
+	getGroupObjectPropertyPath = "/api/getobjectproperty.htm"
+	setGroupObjectPropertyPath = "/api/setobjectproperty.htm"
+)
+
+// NewGroupsService returns a new GroupsService for a client
+func NewGroupsService(client *Client) *GroupsService {
+	return &GroupsService{
+		client: client,
+	}
+}
+
+// GetProperty returns the current value of a property of a group
+func (g *GroupsService) GetProperty(ctx context.Context, id int64, name string) (string, error) {
+	v := url.Values{}
+	v.Set("id", strconv.FormatInt(id, 10))
+	v.Set("name", name)
+
+	var propertyResult struct {
+		Value string `xml:"result"`
+	}
+
+	err := g.client.do(ctx, getGroupObjectPropertyPath, v, &propertyResult)
+	if err != nil {
+		return "", err
+	}
+
+	return propertyResult.Value, nil
+}
+
+// UpdateProperty sets a new value for a property of a group
+func (g *GroupsService) UpdateProperty(ctx context.Context, id int64, name string, value string) error {
+	v := url.Values{}
+	v.Set("id", strconv.FormatInt(id, 10))
+	v.Set("name", name)
+	v.Set("value", value)
+	return g.client.do(ctx, setGroupObjectPropertyPath, v, nil)
+}
@@ -0,0 +1,184 @@
+package prtgapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const historyPath = "/api/historicdata.json"
+
+// History holds the historic data of a sensor, with a time series per channel
+type History struct {
+	SensorID int64
+	From     time.Time
+	To       time.Time
+	Average  time.Duration
+	Channels []*ChannelHistory
+}
+
+// ChannelHistory holds the historic values of a single channel, ordered by time
+type ChannelHistory struct {
+	Name   string
+	Points []HistoryPoint
+}
+
+// HistoryPoint is a single value of a channel
+//
+// Time is the end of the interval the value was averaged over. HasValue is false when PRTG has
+// no value for the interval, e.g. because the sensor was paused. Coverage is the percentage
+// (0 to 100) of the interval that is covered by monitoring data.
+type HistoryPoint struct {
+	Time     time.Time
+	Value    float64
+	HasValue bool
+	Coverage float64
+}
+
+// Channel returns the history of the channel with the given name, or nil when there is none
+func (h *History) Channel(name string) *ChannelHistory {
+	for _, channel := range h.Channels {
+		if channel.Name == name {
+			return channel
+		}
+	}
+	return nil
+}
+
+type historyResponse struct {
+	Rows []map[string]json.RawMessage `json:"histdata"`
+}
+
+// History returns the historic data of a sensor between from and to, averaged over avgInterval.
+//
+// An avgInterval of 0 returns the raw data. PRTG rejects long time ranges for small averages,
+// so the range is split into chunks that are fetched one after the other.
+// The times are sent to PRTG in the timezone of the PRTG core server, see Client.Location,
+// while the times of the points are decoded from the raw dates, which PRTG returns in UTC.
+func (s *SensorsService) History(ctx context.Context, id int64, from time.Time, to time.Time, avgInterval time.Duration) (*History, error) {
+	if !to.After(from) {
+		return nil, fmt.Errorf("The end of the history must be after its start")
+	}
+	if avgInterval < 0 {
+		return nil, fmt.Errorf("The average interval can't be negative")
+	}
+
+	history := &History{
+		SensorID: id,
+		From:     from,
+		To:       to,
+		Average:  avgInterval,
+	}
+	channels := map[string]*ChannelHistory{}
+
+	chunkSize := historyChunkSize(avgInterval)
+	for start := from; start.Before(to); start = start.Add(chunkSize) {
+		end := start.Add(chunkSize)
+		if end.After(to) {
+			end = to
+		}
+
+		v := url.Values{}
+		v.Set("id", strconv.FormatInt(id, 10))
+		v.Set("avg", strconv.FormatInt(int64(avgInterval/time.Second), 10))
+		v.Set("sdate", s.client.formatTime(start))
+		v.Set("edate", s.client.formatTime(end))
+		v.Set("usecaption", "1")
+
+		response := &historyResponse{}
+		err := s.client.do(ctx, historyPath, v, response)
+		if err != nil {
+			return nil, err
+		}
+
+		err = addHistoryRows(channels, response.Rows)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to parse the history of sensor %d: %v", id, err)
+		}
+	}
+
+	names := make([]string, 0, len(channels))
+	for name := range channels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		history.Channels = append(history.Channels, channels[name])
+	}
+
+	return history, nil
+}
+
+// historyChunkSize returns the longest time range that is requested from PRTG at once
+func historyChunkSize(avgInterval time.Duration) time.Duration {
+	const day = 24 * time.Hour
+
+	switch {
+	case avgInterval == 0:
+		return day
+	case avgInterval < time.Hour:
+		return 31 * day
+	case avgInterval < day:
+		return 365 * day
+	default:
+		return 5 * 365 * day
+	}
+}
+
+// addHistoryRows adds the rows of historicdata.json to the channels.
+// Every row holds a datetime, a coverage and a formatted and raw value per channel.
+func addHistoryRows(channels map[string]*ChannelHistory, rows []map[string]json.RawMessage) error {
+	for _, row := range rows {
+		var datetime, coverage flexFloat
+		err := json.Unmarshal(row["datetime_raw"], &datetime)
+		if err != nil {
+			return err
+		}
+		if raw, ok := row["coverage_raw"]; ok {
+			err = json.Unmarshal(raw, &coverage)
+			if err != nil {
+				return err
+			}
+		}
+		t := datetime.time()
+
+		for key, raw := range row {
+			if !strings.HasSuffix(key, "_raw") || key == "datetime_raw" || key == "coverage_raw" {
+				continue
+			}
+			name := strings.TrimSuffix(key, "_raw")
+
+			var value flexFloat
+			err = json.Unmarshal(raw, &value)
+			if err != nil {
+				return fmt.Errorf("Invalid value for channel %s: %v", name, err)
+			}
+
+			channel, ok := channels[name]
+			if !ok {
+				channel = &ChannelHistory{Name: name}
+				channels[name] = channel
+			}
+
+			// Chunks share their boundary, so the last value of a chunk may be returned twice
+			if n := len(channel.Points); n > 0 && !t.After(channel.Points[n-1].Time) {
+				continue
+			}
+
+			channel.Points = append(channel.Points, HistoryPoint{
+				Time:     t,
+				Value:    value.Value,
+				HasValue: value.Valid,
+				// PRTG returns the coverage in hundredths of a percent
+				Coverage: coverage.Value / 100,
+			})
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,119 @@
+package prtgapi
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSensorsService_History(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	responses := map[string]string{
+		"2020-06-17-00-00-00": `{"histdata": [
+			{"datetime": "17-6-2020 12:00:00", "datetime_raw": 43999.5, "Response Time": "100 msec", "Response Time_raw": 100, "Downtime": "0 %", "Downtime_raw": 0, "coverage": "100 %", "coverage_raw": 10000},
+			{"datetime": "18-6-2020 00:00:00", "datetime_raw": 44000.0, "Response Time": "", "Response Time_raw": "", "Downtime": "100 %", "Downtime_raw": 100, "coverage": "0 %", "coverage_raw": 0}
+		]}`,
+		"2020-06-18-00-00-00": `{"histdata": [
+			{"datetime": "18-6-2020 00:00:00", "datetime_raw": 44000.0, "Response Time": "", "Response Time_raw": "", "Downtime": "100 %", "Downtime_raw": 100, "coverage": "0 %", "coverage_raw": 0},
+			{"datetime": "18-6-2020 12:00:00", "datetime_raw": 44000.5, "Response Time": "120 msec", "Response Time_raw": 120, "Downtime": "0 %", "Downtime_raw": 0, "coverage": "50 %", "coverage_raw": 5000}
+		]}`,
+	}
+	requests := 0
+
+	mux.HandleFunc("/api/historicdata.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testAuthentication(t, r)
+		testParams(t, r, map[string]string{
+			"id":         "2345",
+			"avg":        "0",
+			"usecaption": "1",
+		})
+		requests++
+		w.Header().Add("Content-Type", "application/json; charset=UTF-8")
+		w.WriteHeader(200)
+		w.Write([]byte(responses[r.URL.Query().Get("sdate")]))
+	})
+
+	ctx := context.Background()
+	from := time.Date(2020, 6, 17, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2020, 6, 19, 0, 0, 0, 0, time.UTC)
+	got, err := client.Sensors().History(ctx, 2345, from, to, 0)
+	if err != nil {
+		t.Fatalf("Error while getting history: %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("Expected the range to be fetched in 2 chunks, got %d requests", requests)
+	}
+
+	noon17 := time.Date(2020, 6, 17, 12, 0, 0, 0, time.UTC)
+	midnight := time.Date(2020, 6, 18, 0, 0, 0, 0, time.UTC)
+	noon18 := time.Date(2020, 6, 18, 12, 0, 0, 0, time.UTC)
+	want := &History{
+		SensorID: 2345,
+		From:     from,
+		To:       to,
+		Channels: []*ChannelHistory{
+			{Name: "Downtime", Points: []HistoryPoint{
+				{Time: noon17, Value: 0, HasValue: true, Coverage: 100},
+				{Time: midnight, Value: 100, HasValue: true, Coverage: 0},
+				{Time: noon18, Value: 0, HasValue: true, Coverage: 50},
+			}},
+			{Name: "Response Time", Points: []HistoryPoint{
+				{Time: noon17, Value: 100, HasValue: true, Coverage: 100},
+				{Time: midnight, Coverage: 0},
+				{Time: noon18, Value: 120, HasValue: true, Coverage: 50},
+			}},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		for i := range got.Channels {
+			t.Errorf("Got channel %+v, expected %+v", got.Channels[i], want.Channels[i])
+		}
+	}
+	if got.Channel("Response Time") != got.Channels[1] {
+		t.Errorf("Expected Channel to find the Response Time channel")
+	}
+}
+
+func TestSensorsService_HistoryServerTimezone(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+	client.Location = time.FixedZone("CEST", 2*60*60)
+
+	mux.HandleFunc("/api/historicdata.json", func(w http.ResponseWriter, r *http.Request) {
+		testParams(t, r, map[string]string{
+			"sdate": "2020-06-17-02-00-00",
+			"edate": "2020-06-17-14-00-00",
+		})
+		// The formatted dates are in the timezone of the server, the raw dates are in UTC
+		w.Header().Add("Content-Type", "application/json; charset=UTF-8")
+		w.WriteHeader(200)
+		w.Write([]byte(`{"histdata": [
+			{"datetime": "17-6-2020 03:00:00", "datetime_raw": 43999.041666666664, "Ping_raw": 10, "coverage_raw": 10000},
+			{"datetime": "17-6-2020 14:00:00", "datetime_raw": 43999.5, "Ping_raw": 12, "coverage_raw": 10000}
+		]}`))
+	})
+
+	ctx := context.Background()
+	from := time.Date(2020, 6, 17, 0, 0, 0, 0, time.UTC)
+	to := from.Add(12 * time.Hour)
+	got, err := client.Sensors().History(ctx, 2345, from, to, time.Hour)
+	if err != nil {
+		t.Fatalf("Error while getting history: %v", err)
+	}
+
+	points := got.Channel("Ping").Points
+	want := []time.Time{from.Add(time.Hour), to}
+	if len(points) != len(want) {
+		t.Fatalf("Expected %d points, got %d", len(want), len(points))
+	}
+	for i, point := range points {
+		if !point.Time.Equal(want[i]) {
+			t.Errorf("Expected point %d at %v, got %v", i, want[i], point.Time)
+		}
+	}
+}
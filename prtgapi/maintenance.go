@@ -0,0 +1,94 @@
+package prtgapi
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// prtgTimeFormat is the format PRTG uses for dates in properties and query parameters
+const prtgTimeFormat = "2006-01-02-15-04-05"
+
+// MaintenanceWindow represents the one-time maintenance window of an object
+//
+// PRTG stores the start and end without a timezone, they are sent and read in the
+// timezone of the PRTG core server, see Client.Location.
+type MaintenanceWindow struct {
+	Enabled bool
+	Start   time.Time
+	End     time.Time
+}
+
+// durationMinutes converts a duration into the whole minutes PRTG expects, rounding up
+func durationMinutes(duration time.Duration) (int64, error) {
+	if duration <= 0 {
+		return 0, fmt.Errorf("The duration must be positive, got %s", duration)
+	}
+	return int64((duration + time.Minute - 1) / time.Minute), nil
+}
+
+// GetMaintenanceWindow returns the one-time maintenance window of a device
+func (d *DevicesService) GetMaintenanceWindow(ctx context.Context, id int64) (*MaintenanceWindow, error) {
+	enabled, err := d.GetProperty(ctx, id, "maintenable")
+	if err != nil {
+		return nil, err
+	}
+
+	window := &MaintenanceWindow{
+		Enabled: enabled == "1",
+	}
+
+	window.Start, err = d.getTimeProperty(ctx, id, "maintstart")
+	if err != nil {
+		return nil, err
+	}
+
+	window.End, err = d.getTimeProperty(ctx, id, "maintend")
+	if err != nil {
+		return nil, err
+	}
+
+	return window, nil
+}
+
+// SetMaintenanceWindow sets the one-time maintenance window of a device.
+// When the window is disabled only the enabled flag is written.
+func (d *DevicesService) SetMaintenanceWindow(ctx context.Context, id int64, window MaintenanceWindow) error {
+	if !window.Enabled {
+		return d.UpdateProperty(ctx, id, "maintenable", "0")
+	}
+
+	if !window.End.After(window.Start) {
+		return fmt.Errorf("The end of the maintenance window must be after its start")
+	}
+
+	err := d.UpdateProperty(ctx, id, "maintstart", d.client.formatTime(window.Start))
+	if err != nil {
+		return err
+	}
+
+	err = d.UpdateProperty(ctx, id, "maintend", d.client.formatTime(window.End))
+	if err != nil {
+		return err
+	}
+
+	return d.UpdateProperty(ctx, id, "maintenable", "1")
+}
+
+func (d *DevicesService) getTimeProperty(ctx context.Context, id int64, name string) (time.Time, error) {
+	value, err := d.GetProperty(ctx, id, name)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	t, err := d.client.parseTime(value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("Unable to parse %s of object %d: %v", name, id, err)
+	}
+
+	return t, nil
+}
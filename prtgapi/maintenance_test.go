@@ -0,0 +1,98 @@
+package prtgapi
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDevicesService_GetMaintenanceWindow(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	newPropertyStore(t, mux, map[string]map[string]string{"1234": {
+		"maintenable": "1",
+		"maintstart":  "2026-10-19-22-00-00",
+		"maintend":    "2026-10-20-02-30-00",
+	}})
+
+	ctx := context.Background()
+	got, err := client.Devices().GetMaintenanceWindow(ctx, 1234)
+	if err != nil {
+		t.Fatalf("Error while getting maintenance window: %v", err)
+	}
+	want := &MaintenanceWindow{
+		Enabled: true,
+		Start:   time.Date(2026, 10, 19, 22, 0, 0, 0, time.UTC),
+		End:     time.Date(2026, 10, 20, 2, 30, 0, 0, time.UTC),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Got %+v, expected %+v", got, want)
+	}
+}
+
+func TestDevicesService_SetMaintenanceWindow(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	store := newPropertyStore(t, mux, nil)
+
+	ctx := context.Background()
+	err := client.Devices().SetMaintenanceWindow(ctx, 1234, MaintenanceWindow{
+		Enabled: true,
+		Start:   time.Date(2026, 10, 19, 22, 0, 0, 0, time.UTC),
+		End:     time.Date(2026, 10, 20, 2, 30, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("Error while setting maintenance window: %v", err)
+	}
+	want := map[string]string{
+		"maintenable": "1",
+		"maintstart":  "2026-10-19-22-00-00",
+		"maintend":    "2026-10-20-02-30-00",
+	}
+	if got := store.written()["1234"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("Got properties %v, expected %v", got, want)
+	}
+
+	err = client.Devices().SetMaintenanceWindow(ctx, 1234, MaintenanceWindow{
+		Enabled: true,
+		Start:   time.Date(2026, 10, 20, 2, 30, 0, 0, time.UTC),
+		End:     time.Date(2026, 10, 19, 22, 0, 0, 0, time.UTC),
+	})
+	if err == nil {
+		t.Errorf("Expected an error for a maintenance window that ends before it starts")
+	}
+}
+
+func TestDevicesService_MaintenanceWindowServerTimezone(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+	client.Location = time.FixedZone("CEST", 2*60*60)
+
+	store := newPropertyStore(t, mux, nil)
+
+	window := MaintenanceWindow{
+		Enabled: true,
+		Start:   time.Date(2026, 10, 19, 22, 0, 0, 0, time.UTC),
+		End:     time.Date(2026, 10, 20, 2, 30, 0, 0, time.UTC),
+	}
+	ctx := context.Background()
+	err := client.Devices().SetMaintenanceWindow(ctx, 1234, window)
+	if err != nil {
+		t.Fatalf("Error while setting maintenance window: %v", err)
+	}
+	written := store.written()["1234"]
+	if written["maintstart"] != "2026-10-20-00-00-00" || written["maintend"] != "2026-10-20-04-30-00" {
+		t.Errorf("Expected the window in the timezone of the server, got %v", written)
+	}
+
+	got, err := client.Devices().GetMaintenanceWindow(ctx, 1234)
+	if err != nil {
+		t.Fatalf("Error while getting maintenance window: %v", err)
+	}
+	if !got.Start.Equal(window.Start) || !got.End.Equal(window.End) {
+		t.Errorf("Got window %v to %v, expected %v to %v", got.Start, got.End, window.Start, window.End)
+	}
+}
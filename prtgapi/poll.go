@@ -0,0 +1,60 @@
+package prtgapi
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	defaultPollInterval    = time.Second
+	defaultPollMaxInterval = 30 * time.Second
+)
+
+// PollOptions configures how a long running PRTG operation is polled
+//
+// Interval is the delay before the second poll and is doubled after every poll,
+// up to MaxInterval. When left empty an interval of 1 second and a maximum interval
+// of 30 seconds are used. Polling stops as soon as the context is done, so use
+// a context with a deadline to limit the total time spent waiting.
+type PollOptions struct {
+	Interval    time.Duration
+	MaxInterval time.Duration
+}
+
+// poll calls condition until it reports true, returns an error or the context is done
+func poll(ctx context.Context, options PollOptions, condition func(ctx context.Context) (bool, error)) error {
+	interval := options.Interval
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+	maxInterval := options.MaxInterval
+	if maxInterval <= 0 {
+		maxInterval = defaultPollMaxInterval
+	}
+	if maxInterval < interval {
+		maxInterval = interval
+	}
+
+	for {
+		done, err := condition(ctx)
+		if err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		interval *= 2
+		if interval > maxInterval {
+			interval = maxInterval
+		}
+	}
+}
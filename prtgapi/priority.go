@@ -0,0 +1,65 @@
+package prtgapi
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+const (
+	setPriorityPath = "/api/setpriority.htm"
+	setFavoritePath = "/api/simplefavorite.htm"
+)
+
+// SetPriority sets the priority (1 to 5 stars) of a device
+func (d *DevicesService) SetPriority(ctx context.Context, id int64, priority int) error {
+	return d.client.setPriority(ctx, id, priority)
+}
+
+// SetFavorite adds the device to or removes it from the favorites
+func (d *DevicesService) SetFavorite(ctx context.Context, id int64, favorite bool) error {
+	return d.client.setFavorite(ctx, id, favorite)
+}
+
+// SetPriority sets the priority (1 to 5 stars) of a group
+func (g *GroupsService) SetPriority(ctx context.Context, id int64, priority int) error {
+	return g.client.setPriority(ctx, id, priority)
+}
+
+// SetFavorite adds the group to or removes it from the favorites
+func (g *GroupsService) SetFavorite(ctx context.Context, id int64, favorite bool) error {
+	return g.client.setFavorite(ctx, id, favorite)
+}
+
+// SetPriority sets the priority (1 to 5 stars) of a sensor
+func (s *SensorsService) SetPriority(ctx context.Context, id int64, priority int) error {
+	return s.client.setPriority(ctx, id, priority)
+}
+
+// SetFavorite adds the sensor to or removes it from the favorites
+func (s *SensorsService) SetFavorite(ctx context.Context, id int64, favorite bool) error {
+	return s.client.setFavorite(ctx, id, favorite)
+}
+
+func (client *Client) setPriority(ctx context.Context, id int64, priority int) error {
+	if priority < 1 || priority > 5 {
+		return fmt.Errorf("The priority must be between 1 and 5, got %d", priority)
+	}
+
+	v := url.Values{}
+	v.Set("id", strconv.FormatInt(id, 10))
+	v.Set("prio", strconv.Itoa(priority))
+	return client.do(ctx, setPriorityPath, v, nil)
+}
+
+func (client *Client) setFavorite(ctx context.Context, id int64, favorite bool) error {
+	v := url.Values{}
+	v.Set("id", strconv.FormatInt(id, 10))
+	if favorite {
+		v.Set("action", "1")
+	} else {
+		v.Set("action", "0")
+	}
+	return client.do(ctx, setFavoritePath, v, nil)
+}
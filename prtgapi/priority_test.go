@@ -0,0 +1,85 @@
+package prtgapi
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestDevicesService_SetPriority(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/api/setpriority.htm", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testAuthentication(t, r)
+		testParams(t, r, map[string]string{
+			"id":   "1234",
+			"prio": "5",
+		})
+		w.Header().Add("Content-Type", "text/html; charset=UTF-8")
+		w.WriteHeader(200)
+		w.Write([]byte(`<HTML><BODY class="no-content"><B class="no-content">OK</B></BODY></HTML>`))
+	})
+
+	ctx := context.Background()
+	err := client.Devices().SetPriority(ctx, 1234, 5)
+	if err != nil {
+		t.Errorf("Error while setting priority: %v", err)
+	}
+
+	err = client.Devices().SetPriority(ctx, 1234, 6)
+	if err == nil {
+		t.Errorf("Expected an error for priority 6")
+	}
+}
+
+func TestSensorsService_SetFavorite(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/api/simplefavorite.htm", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testAuthentication(t, r)
+		testParams(t, r, map[string]string{
+			"id":     "2345",
+			"action": "1",
+		})
+		w.Header().Add("Content-Type", "text/html; charset=UTF-8")
+		w.WriteHeader(200)
+		w.Write([]byte(`<HTML><BODY class="no-content"><B class="no-content">OK</B></BODY></HTML>`))
+	})
+
+	mux.HandleFunc("/api/table.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testParams(t, r, map[string]string{
+			"content": "sensors",
+			"columns": "objid,type,type_raw,name,priority,favorite",
+			"id":      "1234",
+		})
+		w.Header().Add("Content-Type", "application/json; charset=UTF-8")
+		w.WriteHeader(200)
+		w.Write([]byte(`{"sensors": [{"objid": 2345, "type": "HTTP Advanced", "type_raw": "httpadvanced", "name": "HTTP", "priority": "*****", "priority_raw": 5, "favorite": "", "favorite_raw": 1}]}`))
+	})
+
+	ctx := context.Background()
+	err := client.Sensors().SetFavorite(ctx, 2345, true)
+	if err != nil {
+		t.Fatalf("Error while setting favorite: %v", err)
+	}
+
+	got, err := client.Sensors().List(ctx, SensorListOptions{
+		ID:      1234,
+		Columns: []string{"type", "type_raw", "name", "priority", "favorite"},
+	})
+	if err != nil {
+		t.Fatalf("Error while listing sensors: %v", err)
+	}
+	want := []*Sensor{
+		&Sensor{ID: 2345, Name: "HTTP", Type: "HTTP Advanced", RawType: "httpadvanced", Priority: 5, Favorite: true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Got %+v, expected %+v", got[0], want[0])
+	}
+}
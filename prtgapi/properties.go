@@ -0,0 +1,56 @@
+package prtgapi
+
+import (
+	"context"
+	"sync"
+)
+
+// maxConcurrentPropertyReads limits the number of simultaneous requests made by getProperties
+const maxConcurrentPropertyReads = 8
+
+type propertyGetter func(ctx context.Context, id int64, name string) (string, error)
+type propertySetter func(ctx context.Context, id int64, name string, value string) error
+
+// getProperties reads the given properties of an object concurrently.
+// The first error cancels the outstanding reads and is returned.
+func getProperties(ctx context.Context, get propertyGetter, id int64, names []string) (map[string]string, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+	)
+	values := make(map[string]string, len(names))
+	semaphore := make(chan struct{}, maxConcurrentPropertyReads)
+
+	for _, name := range names {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+
+			semaphore <- struct{}{}
+			value, err := get(ctx, id, name)
+			<-semaphore
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+					cancel()
+				}
+				return
+			}
+			values[name] = value
+		}(name)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	return values, nil
+}
@@ -2,8 +2,11 @@ package prtgapi
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"net/url"
 	"strconv"
+	"time"
 )
 
 // SensorsService handles communication with the sensor related methods of the PRTG API
@@ -14,26 +17,117 @@ type sensorList struct {
 }
 
 // Sensor represents a PRTG sensor
+//
+// Only the fields for the columns requested through SensorListOptions.Columns are filled,
+// by default these are ID, Name, Type and RawType.
 type Sensor struct {
-	ID      int64 `json:"objid"`
-	Name    string
-	Type    string
-	RawType string `json:"type_raw"`
+	ID            int64 `json:"objid"`
+	Name          string
+	Type          string
+	RawType       string        `json:"type_raw"`
+	Status        Status        `json:"status_raw"`
+	StatusText    string        `json:"status"`
+	Message       string        `json:"message_raw"`
+	LastValue     string        `json:"lastvalue"`
+	LastValueRaw  float64       `json:"-"`
+	LastCheck     time.Time     `json:"-"`
+	Interval      time.Duration `json:"-"`
+	Device        string        `json:"device"`
+	Group         string        `json:"group"`
+	ParentID      int64         `json:"parentid"`
+	Tags          []string      `json:"-"`
+	Active        bool          `json:"-"`
+	Priority      int           `json:"priority_raw"`
+	Favorite      bool          `json:"-"`
+	Downtime      float64       `json:"-"`
+	DowntimeTotal time.Duration `json:"-"`
+	DowntimeSince time.Duration `json:"-"`
+}
+
+// UnmarshalJSON decodes a sensor row from a PRTG table, converting the raw dates,
+// durations, tags and the PRTG style booleans into their Go counterparts
+func (s *Sensor) UnmarshalJSON(data []byte) error {
+	type sensor Sensor
+	aux := struct {
+		*sensor
+		LastValueRaw  flexFloat `json:"lastvalue_raw"`
+		LastCheck     flexFloat `json:"lastcheck_raw"`
+		Interval      flexFloat `json:"interval_raw"`
+		Tags          string    `json:"tags"`
+		Active        int       `json:"active_raw"`
+		Favorite      int       `json:"favorite_raw"`
+		Downtime      flexFloat `json:"downtime_raw"`
+		DowntimeTotal flexFloat `json:"downtimetime_raw"`
+		DowntimeSince flexFloat `json:"downtimesince_raw"`
+	}{
+		sensor: (*sensor)(s),
+	}
+
+	err := json.Unmarshal(data, &aux)
+	if err != nil {
+		return err
+	}
+
+	s.LastValueRaw = aux.LastValueRaw.Value
+	s.LastCheck = aux.LastCheck.time()
+	s.Interval = aux.Interval.seconds()
+	if aux.Tags != "" {
+		s.Tags = parseTags(aux.Tags)
+	}
+	s.Active = aux.Active != 0
+	s.Favorite = aux.Favorite != 0
+	s.Downtime = aux.Downtime.Value
+	s.DowntimeTotal = aux.DowntimeTotal.seconds()
+	s.DowntimeSince = aux.DowntimeSince.seconds()
+
+	return nil
 }
 
-// SensorListOptions can be used to filter sensors when calling List
+// DefaultSensorColumns are the columns that are fetched when no columns are given in SensorListOptions
+var DefaultSensorColumns = []string{"objid", "type", "type_raw", "name"}
+
+// AllSensorColumns are all the columns that can be mapped onto a Sensor
+var AllSensorColumns = []string{
+	"objid", "name", "type", "status", "message", "lastvalue", "lastcheck", "interval",
+	"device", "group", "parentid", "tags", "active", "priority", "favorite",
+	"downtime", "downtimetime", "downtimesince",
+}
+
+// SensorListOptions can be used to filter sensors when calling List or Get*
+//
+// Columns selects which columns are fetched from PRTG, when empty DefaultSensorColumns is used.
+// Use AllSensorColumns to fill every field of the Sensor. The objid column is always fetched.
 //
 // Currently it is possible to filter on
 // * ID (this refers to the ID of the device)
+// * ParentGroupID (all sensors in the group and its subgroups, can't be combined with ID)
 // * Tags
+// * Status (sensors matching any of the statuses, see DownStatuses and PausedStatuses)
+// * RawType (e.g. httpadvanced)
+// * NamePattern (sensors with a name containing the pattern)
+// * Other filters, like
+//	map[string]string{
+//		"objid": "12345"
+//	}
 type SensorListOptions struct {
-	ID   int64
-	Tags []string
+	ID            int64
+	ParentGroupID int64
+	Tags          []string
+	Status        []Status
+	RawType       string
+	NamePattern   string
+	Filter        map[string]string
+	Columns       []string
 }
 
 const (
 	sensorListPath              = "/api/table.json"
 	sensorPausePath             = "/api/pause.htm"
+	sensorPauseForPath          = "/api/pauseobjectfor.htm"
+	sensorScanPath              = "/api/scannow.htm"
+	duplicateSensorPath         = "/api/duplicateobject.htm"
+	deleteSensorPath            = "/api/deleteobject.htm"
+	renameSensorPath            = "/api/rename.htm"
 	getSensorObjectPropertyPath = "/api/getobjectproperty.htm"
 	setSensorObjectPropertyPath = "/api/setobjectproperty.htm"
 )
@@ -48,17 +142,38 @@ func NewSensorsService(client *Client) *SensorsService {
 
 // List returns a list of sensor objects that match the given options
 func (s *SensorsService) List(ctx context.Context, options SensorListOptions) ([]*Sensor, error) {
+	if options.ID != 0 && options.ParentGroupID != 0 {
+		return nil, fmt.Errorf("Sensors can be filtered on either the ID or the ParentGroupID, not both")
+	}
+
 	v := url.Values{}
 	v.Set("content", "sensors")
-	v.Set("columns", "objid,type,type_raw,name")
+	v.Set("columns", columnsParam(options.Columns, DefaultSensorColumns))
 	if options.ID != 0 {
 		v.Set("id", strconv.FormatInt(options.ID, 10))
 	}
+	if options.ParentGroupID != 0 {
+		v.Set("id", strconv.FormatInt(options.ParentGroupID, 10))
+	}
 	if len(options.Tags) > 0 {
 		for _, tag := range options.Tags {
 			v.Set("filter_tags", tag)
 		}
 	}
+	for _, status := range options.Status {
+		v.Add("filter_status", strconv.Itoa(int(status)))
+	}
+	if options.RawType != "" {
+		v.Set("filter_type", options.RawType)
+	}
+	if options.NamePattern != "" {
+		v.Set("filter_name", "@sub("+options.NamePattern+")")
+	}
+	if len(options.Filter) > 0 {
+		for key, value := range options.Filter {
+			v.Set("filter_"+key, value)
+		}
+	}
 
 	sensorList := &sensorList{}
 	err := s.client.do(ctx, sensorListPath, v, sensorList)
@@ -69,6 +184,82 @@ func (s *SensorsService) List(ctx context.Context, options SensorListOptions) ([
 	return sensorList.Items, nil
 }
 
+// Get returns a single sensor if there is only one sensor matching the given options
+func (s *SensorsService) Get(ctx context.Context, options SensorListOptions) (*Sensor, error) {
+	sensors, err := s.List(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+
+	switch len(sensors) {
+	case 0:
+		return nil, nil
+	case 1:
+		return sensors[0], nil
+	default:
+		return nil, fmt.Errorf("More than one sensor matched the query")
+	}
+}
+
+// GetByID returns a single sensor identified by the id and given options
+func (s *SensorsService) GetByID(ctx context.Context, id int64, options SensorListOptions) (*Sensor, error) {
+	filter := map[string]string{}
+	for key, value := range options.Filter {
+		filter[key] = value
+	}
+	filter["objid"] = strconv.FormatInt(id, 10)
+	options.Filter = filter
+
+	return s.Get(ctx, options)
+}
+
+// Duplicate copies the sensor identified by sensorID onto the device identified by targetDeviceID
+// and returns the new sensor. PRTG pauses duplicated sensors, use Unpause to start monitoring.
+func (s *SensorsService) Duplicate(ctx context.Context, sensorID int64, targetDeviceID int64, name string) (*Sensor, error) {
+	v := url.Values{}
+	v.Set("id", strconv.FormatInt(sensorID, 10))
+	v.Set("targetid", strconv.FormatInt(targetDeviceID, 10))
+	v.Set("name", name)
+
+	res := &redirectResponse{}
+	err := s.client.do(ctx, duplicateSensorPath, v, res)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get the sensorID from the redirect response
+	newSensorID, err := res.objectID()
+	if err != nil {
+		return nil, err
+	}
+
+	newSensor, err := s.GetByID(ctx, newSensorID, SensorListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if newSensor == nil {
+		return nil, fmt.Errorf("Sensor %d was not found after duplicating it", newSensorID)
+	}
+
+	return newSensor, nil
+}
+
+// Delete deletes a sensor
+func (s *SensorsService) Delete(ctx context.Context, id int64) error {
+	v := url.Values{}
+	v.Set("id", strconv.FormatInt(id, 10))
+	v.Set("approve", "1")
+	return s.client.do(ctx, deleteSensorPath, v, nil)
+}
+
+// Rename changes the name of a sensor
+func (s *SensorsService) Rename(ctx context.Context, id int64, name string) error {
+	v := url.Values{}
+	v.Set("id", strconv.FormatInt(id, 10))
+	v.Set("value", name)
+	return s.client.do(ctx, renameSensorPath, v, nil)
+}
+
 // GetProperty returns the current value of a property
 func (s *SensorsService) GetProperty(ctx context.Context, id int64, name string) (string, error) {
 	v := url.Values{}
@@ -105,6 +296,21 @@ func (s *SensorsService) Pause(ctx context.Context, id int64, message string) er
 	return s.client.do(ctx, sensorPausePath, v, nil)
 }
 
+// PauseFor pauses a sensor for the given duration, after which PRTG resumes it automatically.
+// The duration is rounded up to whole minutes.
+func (s *SensorsService) PauseFor(ctx context.Context, id int64, duration time.Duration, message string) error {
+	minutes, err := durationMinutes(duration)
+	if err != nil {
+		return err
+	}
+
+	v := url.Values{}
+	v.Set("id", strconv.FormatInt(id, 10))
+	v.Set("duration", strconv.FormatInt(minutes, 10))
+	v.Set("pausemsg", message)
+	return s.client.do(ctx, sensorPauseForPath, v, nil)
+}
+
 // Unpause unpauses a sensor
 func (s *SensorsService) Unpause(ctx context.Context, id int64) error {
 	v := url.Values{}
@@ -112,3 +318,51 @@ func (s *SensorsService) Unpause(ctx context.Context, id int64) error {
 	v.Set("action", "1")
 	return s.client.do(ctx, sensorPausePath, v, nil)
 }
+
+// ScanNow requests PRTG to scan the sensor immediately
+func (s *SensorsService) ScanNow(ctx context.Context, id int64) error {
+	v := url.Values{}
+	v.Set("id", strconv.FormatInt(id, 10))
+	return s.client.do(ctx, sensorScanPath, v, nil)
+}
+
+// ScanAndWait requests PRTG to scan the sensor immediately and waits until the scan has finished.
+//
+// The scan is considered finished when the last check time of the sensor has moved forward.
+// The returned sensor has its status, message and last check time filled.
+func (s *SensorsService) ScanAndWait(ctx context.Context, id int64, options PollOptions) (*Sensor, error) {
+	listOptions := SensorListOptions{
+		Columns: []string{"objid", "name", "status", "message", "lastcheck"},
+	}
+
+	before, err := s.GetByID(ctx, id, listOptions)
+	if err != nil {
+		return nil, err
+	}
+	if before == nil {
+		return nil, fmt.Errorf("Sensor %d was not found", id)
+	}
+
+	err = s.ScanNow(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var sensor *Sensor
+	err = poll(ctx, options, func(ctx context.Context) (bool, error) {
+		var err error
+		sensor, err = s.GetByID(ctx, id, listOptions)
+		if err != nil {
+			return false, err
+		}
+		if sensor == nil {
+			return false, fmt.Errorf("Sensor %d was not found while waiting for the scan", id)
+		}
+		return sensor.LastCheck.After(before.LastCheck), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return sensor, nil
+}
@@ -0,0 +1,180 @@
+package prtgapi
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	addSensorPath         = "/api/addsensor2.htm"
+	addSensorProgressPath = "/api/getaddsensorprogress.htm"
+	addSensorCommitPath   = "/api/addsensor5.htm"
+)
+
+// SensorSpec describes a sensor that is added with Add
+//
+// Type is the raw sensor type, e.g. "http", "ping" or "sslcertificate".
+// Settings holds the other fields of the add sensor form by their PRTG name, e.g.
+//
+//	map[string]string{
+//		"httpurl": "https://www.example.com/health",
+//	}
+//
+// When Interval is set the scanning interval isn't inherited from the device.
+// WaitOptions configures the polling while PRTG prepares the sensor.
+type SensorSpec struct {
+	Type        string
+	Name        string
+	Tags        []string
+	Priority    int
+	Interval    time.Duration
+	Settings    map[string]string
+	WaitOptions PollOptions
+}
+
+type addSensorProgress struct {
+	Progress     flexFloat `json:"progress"`
+	ErrorMessage string    `json:"errormessage"`
+}
+
+// Add adds a new sensor to the device identified by deviceID and returns the created sensors.
+//
+// This follows the add sensor wizard of PRTG: the sensor is prepared under a temporary ID,
+// which is polled until PRTG is ready, after which the sensor is committed.
+// Some sensor types create more than one sensor, so all new sensors on the device are returned.
+//
+// PRTG doesn't return the IDs of the new sensors, so they are found by comparing the sensors of
+// the device before and after the commit, keeping only those of the requested type and name.
+// Sensors of the same type and name that are added to the device at the same time, e.g. by an
+// auto-discovery or another client, can't be told apart and are returned as well.
+func (s *SensorsService) Add(ctx context.Context, deviceID int64, spec SensorSpec) ([]*Sensor, error) {
+	if spec.Type == "" {
+		return nil, fmt.Errorf("A sensor type is required to add a sensor")
+	}
+	if spec.Priority != 0 && (spec.Priority < 1 || spec.Priority > 5) {
+		return nil, fmt.Errorf("The priority must be between 1 and 5, got %d", spec.Priority)
+	}
+	if err := validateTags(spec.Tags); err != nil {
+		return nil, err
+	}
+
+	v := url.Values{}
+	v.Set("id", strconv.FormatInt(deviceID, 10))
+	v.Set("sensortype", spec.Type)
+
+	res := &redirectResponse{}
+	err := s.client.do(ctx, addSensorPath, v, res)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpID, err := temporaryID(res.Location)
+	if err != nil {
+		return nil, err
+	}
+
+	err = poll(ctx, spec.WaitOptions, func(ctx context.Context) (bool, error) {
+		v := url.Values{}
+		v.Set("id", strconv.FormatInt(deviceID, 10))
+		v.Set("tmpid", tmpID)
+
+		progress := &addSensorProgress{}
+		err := s.client.do(ctx, addSensorProgressPath, v, progress)
+		if err != nil {
+			return false, err
+		}
+		if progress.ErrorMessage != "" {
+			return false, fmt.Errorf("PRTG failed to prepare the %s sensor: %s", spec.Type, progress.ErrorMessage)
+		}
+		return progress.Progress.Valid && progress.Progress.Value >= 100, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	listOptions := SensorListOptions{ID: deviceID, RawType: spec.Type}
+	before, err := s.List(ctx, listOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.client.do(ctx, addSensorCommitPath, addSensorValues(deviceID, tmpID, spec), &redirectResponse{})
+	if err != nil {
+		return nil, err
+	}
+
+	after, err := s.List(ctx, listOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	existing := map[int64]bool{}
+	for _, sensor := range before {
+		existing[sensor.ID] = true
+	}
+
+	var added []*Sensor
+	for _, sensor := range after {
+		if existing[sensor.ID] || sensor.RawType != spec.Type {
+			continue
+		}
+		if spec.Name != "" && sensor.Name != spec.Name {
+			continue
+		}
+		added = append(added, sensor)
+	}
+	if len(added) == 0 {
+		return nil, fmt.Errorf("PRTG accepted the %s sensor, but no new sensor was found on device %d", spec.Type, deviceID)
+	}
+
+	return added, nil
+}
+
+func addSensorValues(deviceID int64, tmpID string, spec SensorSpec) url.Values {
+	v := url.Values{}
+	for name, value := range spec.Settings {
+		// The fields of the add sensor form end with an underscore
+		if !strings.HasSuffix(name, "_") {
+			name += "_"
+		}
+		v.Set(name, value)
+	}
+
+	v.Set("id", strconv.FormatInt(deviceID, 10))
+	v.Set("tmpid", tmpID)
+	v.Set("sensortype", spec.Type)
+	if spec.Name != "" {
+		v.Set("name_", spec.Name)
+	}
+	if len(spec.Tags) > 0 {
+		v.Set("tags_", strings.Join(spec.Tags, ","))
+	}
+	if spec.Priority != 0 {
+		v.Set("priority_", strconv.Itoa(spec.Priority))
+	}
+	if spec.Interval != 0 {
+		v.Set("intervalgroup_", "0")
+		v.Set("interval_", formatInterval(spec.Interval))
+	}
+
+	return v
+}
+
+// temporaryID returns the tmpid PRTG redirected to after starting the add sensor wizard
+func temporaryID(location string) (string, error) {
+	u, err := url.Parse(location)
+	if err != nil {
+		return "", err
+	}
+
+	tmpID := u.Query().Get("tmpid")
+	if tmpID == "" {
+		return "", fmt.Errorf("No temporary sensor ID found in the redirect location %q", location)
+	}
+
+	return tmpID, nil
+}
@@ -0,0 +1,317 @@
+package prtgapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSensorsService_ScanAndWait(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	scanned := false
+	mux.HandleFunc("/api/scannow.htm", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testAuthentication(t, r)
+		testParams(t, r, map[string]string{
+			"id": "2345",
+		})
+		scanned = true
+		w.Header().Add("Content-Type", "text/html; charset=UTF-8")
+		w.WriteHeader(200)
+		w.Write([]byte(`<HTML><BODY class="no-content"><B class="no-content">OK</B></BODY></HTML>`))
+	})
+
+	polls := 0
+	mux.HandleFunc("/api/table.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testParams(t, r, map[string]string{
+			"content":      "sensors",
+			"columns":      "objid,name,status,message,lastcheck",
+			"filter_objid": "2345",
+		})
+		lastCheck := 44000.5
+		status, message := 3, "OK"
+		if scanned {
+			polls++
+		}
+		if polls > 1 {
+			lastCheck, status, message = 44000.6, 5, "Connection refused"
+		}
+		w.Header().Add("Content-Type", "application/json; charset=UTF-8")
+		w.WriteHeader(200)
+		fmt.Fprintf(w, `{"sensors": [{"objid": 2345, "name": "HTTP", "status_raw": %d, "message_raw": %q, "lastcheck_raw": %v}]}`, status, message, lastCheck)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	got, err := client.Sensors().ScanAndWait(ctx, 2345, PollOptions{Interval: time.Millisecond})
+	if err != nil {
+		t.Fatalf("Error while scanning sensor: %v", err)
+	}
+	if got.Status != StatusDown || got.Message != "Connection refused" {
+		t.Errorf("Expected status Down with message 'Connection refused', got %s with message %q", got.Status, got.Message)
+	}
+	if want := time.Date(2020, 6, 18, 14, 24, 0, 0, time.UTC); !got.LastCheck.Equal(want) {
+		t.Errorf("Expected last check %v, got %v", want, got.LastCheck)
+	}
+}
+
+func TestSensorsService_Duplicate(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/api/duplicateobject.htm", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testAuthentication(t, r)
+		testParams(t, r, map[string]string{
+			"id":       "2345",
+			"targetid": "1234",
+			"name":     "HTTP copy",
+		})
+		w.Header().Add("Content-Type", "text/html; charset=UTF-8")
+		w.Header().Add("Location", "/sensor.htm?id=2346")
+		w.WriteHeader(302)
+	})
+
+	mux.HandleFunc("/api/table.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testParams(t, r, map[string]string{
+			"content":      "sensors",
+			"filter_objid": "2346",
+		})
+		w.Header().Add("Content-Type", "application/json; charset=UTF-8")
+		w.WriteHeader(200)
+		w.Write([]byte(`{"sensors": [{"objid": 2346, "type": "HTTP Advanced", "type_raw": "httpadvanced", "name": "HTTP copy"}]}`))
+	})
+
+	ctx := context.Background()
+	got, err := client.Sensors().Duplicate(ctx, 2345, 1234, "HTTP copy")
+	if err != nil {
+		t.Fatalf("Error while duplicating sensor: %v", err)
+	}
+	if want := (&Sensor{ID: 2346, Name: "HTTP copy", Type: "HTTP Advanced", RawType: "httpadvanced"}); !reflect.DeepEqual(got, want) {
+		t.Errorf("Got %+v, expected %+v", got, want)
+	}
+}
+
+func TestSensorsService_DeleteAndRename(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/api/deleteobject.htm", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testAuthentication(t, r)
+		testParams(t, r, map[string]string{
+			"id":      "2345",
+			"approve": "1",
+		})
+		w.Header().Add("Content-Type", "text/html; charset=UTF-8")
+		w.WriteHeader(200)
+		w.Write([]byte(`<HTML><BODY class="no-content"><B class="no-content">OK</B></BODY></HTML>`))
+	})
+
+	mux.HandleFunc("/api/rename.htm", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testAuthentication(t, r)
+		testParams(t, r, map[string]string{
+			"id":    "2345",
+			"value": "HTTP renamed",
+		})
+		w.Header().Add("Content-Type", "text/html; charset=UTF-8")
+		w.WriteHeader(200)
+		w.Write([]byte(`<HTML><BODY class="no-content"><B class="no-content">OK</B></BODY></HTML>`))
+	})
+
+	ctx := context.Background()
+	err := client.Sensors().Rename(ctx, 2345, "HTTP renamed")
+	if err != nil {
+		t.Errorf("Error while renaming sensor: %v", err)
+	}
+	err = client.Sensors().Delete(ctx, 2345)
+	if err != nil {
+		t.Errorf("Error while deleting sensor: %v", err)
+	}
+}
+
+func TestSensorsService_Add(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/api/addsensor2.htm", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testAuthentication(t, r)
+		testParams(t, r, map[string]string{
+			"id":         "1234",
+			"sensortype": "http",
+		})
+		w.Header().Add("Location", "/addsensor4.htm?id=1234&tmpid=7")
+		w.WriteHeader(302)
+	})
+
+	progress := 0
+	mux.HandleFunc("/api/getaddsensorprogress.htm", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testParams(t, r, map[string]string{
+			"id":    "1234",
+			"tmpid": "7",
+		})
+		progress += 50
+		w.Header().Add("Content-Type", "application/json; charset=UTF-8")
+		w.WriteHeader(200)
+		fmt.Fprintf(w, `{"progress": "%d", "targeturl": "/addsensor4.htm?id=1234&tmpid=7"}`, progress)
+	})
+
+	committed := false
+	mux.HandleFunc("/api/addsensor5.htm", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testParams(t, r, map[string]string{
+			"id":         "1234",
+			"tmpid":      "7",
+			"sensortype": "http",
+			"name_":      "Health",
+			"tags_":      "k8s-ingress",
+			"priority_":  "4",
+			"httpurl_":   "https://www.example.com/health",
+		})
+		if progress < 100 {
+			t.Errorf("Sensor committed before PRTG finished preparing it")
+		}
+		committed = true
+		w.Header().Add("Location", "/device.htm?id=1234")
+		w.WriteHeader(302)
+	})
+
+	// Another client added an HTTP sensor with a different name at the same time
+	mux.HandleFunc("/api/table.json", func(w http.ResponseWriter, r *http.Request) {
+		testParams(t, r, map[string]string{
+			"content":     "sensors",
+			"id":          "1234",
+			"filter_type": "http",
+		})
+		w.Header().Add("Content-Type", "application/json; charset=UTF-8")
+		w.WriteHeader(200)
+		if committed {
+			w.Write([]byte(`{"sensors": [{"objid": 2346, "type_raw": "http", "name": "Health"}, {"objid": 2350, "type_raw": "http", "name": "Health"}, {"objid": 2351, "type_raw": "http", "name": "Login"}]}`))
+		} else {
+			w.Write([]byte(`{"sensors": [{"objid": 2346, "type_raw": "http", "name": "Health"}]}`))
+		}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	got, err := client.Sensors().Add(ctx, 1234, SensorSpec{
+		Type:     "http",
+		Name:     "Health",
+		Tags:     []string{"k8s-ingress"},
+		Priority: 4,
+		Settings: map[string]string{
+			"httpurl": "https://www.example.com/health",
+		},
+		WaitOptions: PollOptions{Interval: time.Millisecond},
+	})
+	if err != nil {
+		t.Fatalf("Error while adding sensor: %v", err)
+	}
+	if want := []*Sensor{&Sensor{ID: 2350, Name: "Health", RawType: "http"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Got %+v, expected %+v", got, want)
+	}
+}
+
+func TestSensorsService_ListFilters(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/api/table.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testAuthentication(t, r)
+		testParams(t, r, map[string]string{
+			"content":     "sensors",
+			"columns":     "objid,name,status,lastvalue,lastcheck,interval,device,group,tags,active,downtime,downtimetime,downtimesince",
+			"id":          "900",
+			"filter_type": "httpadvanced",
+			"filter_name": "@sub(health)",
+		})
+		if statuses := r.URL.Query()["filter_status"]; !reflect.DeepEqual(statuses, []string{"5", "4"}) {
+			t.Errorf("Expected status filters 5 and 4, got %v", statuses)
+		}
+		w.Header().Add("Content-Type", "application/json; charset=UTF-8")
+		w.WriteHeader(200)
+		w.Write(sensorListRichJSON)
+	})
+
+	ctx := context.Background()
+	got, err := client.Sensors().List(ctx, SensorListOptions{
+		ParentGroupID: 900,
+		Status:        []Status{StatusDown, StatusWarning},
+		RawType:       "httpadvanced",
+		NamePattern:   "health",
+		Columns:       []string{"name", "status", "lastvalue", "lastcheck", "interval", "device", "group", "tags", "active", "downtime", "downtimetime", "downtimesince"},
+	})
+	if err != nil {
+		t.Fatalf("Error while listing sensors: %v", err)
+	}
+	want := []*Sensor{
+		&Sensor{
+			ID:            2345,
+			Name:          "health",
+			Status:        StatusDown,
+			StatusText:    "Down",
+			LastValue:     "1.024 msec",
+			LastValueRaw:  1024,
+			LastCheck:     time.Date(2020, 6, 18, 14, 24, 0, 0, time.UTC),
+			Interval:      time.Minute,
+			Device:        "testdevice",
+			Group:         "k8s",
+			Tags:          []string{"k8s-ingress", "httpsensor"},
+			Active:        true,
+			Downtime:      0.5,
+			DowntimeTotal: 90 * time.Minute,
+			DowntimeSince: 5 * time.Minute,
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Got %+v, expected %+v", got[0], want[0])
+	}
+
+	_, err = client.Sensors().List(ctx, SensorListOptions{ID: 1234, ParentGroupID: 900})
+	if err == nil {
+		t.Errorf("Expected an error when filtering on both ID and ParentGroupID")
+	}
+}
+
+var sensorListRichJSON = []byte(`{
+	"prtg-version": "19.4.53.1912",
+	"treesize": 1,
+	"sensors": [
+		{
+			"objid": 2345,
+			"name": "health",
+			"status": "Down",
+			"status_raw": 5,
+			"lastvalue": "1.024 msec",
+			"lastvalue_raw": 1024.0000,
+			"lastcheck": "18-6-2020 14:24:00 [5 s ago]",
+			"lastcheck_raw": 44000.6,
+			"interval": "60 s",
+			"interval_raw": 60,
+			"device": "testdevice",
+			"group": "k8s",
+			"tags": "k8s-ingress httpsensor",
+			"active": true,
+			"active_raw": -1,
+			"downtime": "0.5000 %",
+			"downtime_raw": 0.5,
+			"downtimetime": "1 h 30 m",
+			"downtimetime_raw": 5400,
+			"downtimesince": "5 m",
+			"downtimesince_raw": 300
+		}
+	]
+}`)
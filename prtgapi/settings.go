@@ -0,0 +1,326 @@
+package prtgapi
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DependencyType determines which object an object depends on
+type DependencyType int
+
+// The dependency types supported by PRTG
+const (
+	// DependencyParent makes the object depend on its parent
+	DependencyParent DependencyType = 0
+	// DependencyObject makes the object depend on another object
+	DependencyObject DependencyType = 1
+	// DependencyMaster makes a sensor the master object of its parent device, only valid for sensors
+	DependencyMaster DependencyType = 2
+)
+
+// DeviceSettings holds the typed settings of a device
+//
+// GetSettings fills every field. For ApplySettings only the fields that are not nil are
+// taken into account, so a settings struct with only Location set just updates the location.
+//
+// The Inherit* fields control whether the device takes the related settings from its parent:
+// InheritInterval for the scanning interval, InheritSchedule for the schedules,
+// dependencies and maintenance window and InheritAccessRights for the access rights.
+type DeviceSettings struct {
+	Name       *string
+	Host       *string
+	Location   *string
+	Comments   *string
+	ServiceURL *string
+	Icon       *string
+
+	Interval        *time.Duration
+	InheritInterval *bool
+
+	DependencyType *DependencyType
+	DependencyID   *int64
+
+	InheritSchedule     *bool
+	InheritAccessRights *bool
+}
+
+// setting maps a field of a settings struct onto a PRTG property
+type setting struct {
+	property string
+	// format returns the PRTG value of the field, ok is false when the field is nil
+	format func(settings interface{}) (value string, ok bool)
+	// parse sets the field from a PRTG value
+	parse func(settings interface{}, value string) error
+}
+
+var deviceSettings = []setting{
+	stringSetting("name", func(s interface{}) **string { return &s.(*DeviceSettings).Name }),
+	stringSetting("host", func(s interface{}) **string { return &s.(*DeviceSettings).Host }),
+	stringSetting("location", func(s interface{}) **string { return &s.(*DeviceSettings).Location }),
+	stringSetting("comments", func(s interface{}) **string { return &s.(*DeviceSettings).Comments }),
+	stringSetting("serviceurl", func(s interface{}) **string { return &s.(*DeviceSettings).ServiceURL }),
+	stringSetting("deviceicon", func(s interface{}) **string { return &s.(*DeviceSettings).Icon }),
+	intervalSetting("interval", func(s interface{}) **time.Duration { return &s.(*DeviceSettings).Interval }),
+	boolSetting("intervalgroup", func(s interface{}) **bool { return &s.(*DeviceSettings).InheritInterval }),
+	dependencyTypeSetting("dependencytype", func(s interface{}) **DependencyType { return &s.(*DeviceSettings).DependencyType }),
+	int64Setting("dependency", func(s interface{}) **int64 { return &s.(*DeviceSettings).DependencyID }),
+	boolSetting("scheduledependency", func(s interface{}) **bool { return &s.(*DeviceSettings).InheritSchedule }),
+	boolSetting("accessgroup", func(s interface{}) **bool { return &s.(*DeviceSettings).InheritAccessRights }),
+}
+
+// GetSettings returns the settings of a device, the properties are read concurrently
+func (d *DevicesService) GetSettings(ctx context.Context, id int64) (*DeviceSettings, error) {
+	settings := &DeviceSettings{}
+	err := getSettings(ctx, d.GetProperty, id, deviceSettings, settings)
+	if err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// ApplySettings writes the fields of settings that are not nil to a device.
+//
+// The current values are read concurrently first and only the properties whose value
+// differs are written. The names of the written properties are returned.
+// A changed dependency is written through SetDependency, so it is validated the same way.
+func (d *DevicesService) ApplySettings(ctx context.Context, id int64, settings DeviceSettings) ([]string, error) {
+	if settings.DependencyType != nil && *settings.DependencyType == DependencyMaster {
+		return nil, fmt.Errorf("Only sensors can be the master object of their parent")
+	}
+
+	dependencyType, dependencyID := settings.DependencyType, settings.DependencyID
+	settings.DependencyType, settings.DependencyID = nil, nil
+
+	written, err := applySettings(ctx, d.GetProperty, d.UpdateProperty, id, deviceSettings, &settings, &DeviceSettings{})
+	if err != nil || (dependencyType == nil && dependencyID == nil) {
+		return written, err
+	}
+
+	current, err := d.GetDependency(ctx, id)
+	if err != nil {
+		return written, err
+	}
+
+	dependency := *current
+	if dependencyType != nil {
+		dependency.Type = *dependencyType
+	}
+	if dependencyID != nil {
+		dependency.ObjectID = *dependencyID
+	}
+	if dependency.Type != DependencyObject {
+		dependency.ObjectID = 0
+	}
+	if dependency == *current {
+		return written, nil
+	}
+
+	err = d.SetDependency(ctx, id, dependency)
+	if err != nil {
+		return written, err
+	}
+	written = append(written, "dependencytype")
+	if dependency.Type == DependencyObject {
+		written = append(written, "dependency")
+	}
+
+	return written, nil
+}
+
+func getSettings(ctx context.Context, get propertyGetter, id int64, descriptors []setting, settings interface{}) error {
+	names := make([]string, len(descriptors))
+	for i, descriptor := range descriptors {
+		names[i] = descriptor.property
+	}
+
+	values, err := getProperties(ctx, get, id, names)
+	if err != nil {
+		return err
+	}
+
+	for _, descriptor := range descriptors {
+		err = descriptor.parse(settings, values[descriptor.property])
+		if err != nil {
+			return fmt.Errorf("Unable to parse property %s of object %d: %v", descriptor.property, id, err)
+		}
+	}
+
+	return nil
+}
+
+// applySettings writes the set fields of settings that differ from the current values.
+// current must be an empty settings struct of the same type, it is used to normalize the current values.
+func applySettings(ctx context.Context, get propertyGetter, set propertySetter, id int64, descriptors []setting, settings interface{}, current interface{}) ([]string, error) {
+	var changes []setting
+	var names []string
+	for _, descriptor := range descriptors {
+		if _, ok := descriptor.format(settings); ok {
+			changes = append(changes, descriptor)
+			names = append(names, descriptor.property)
+		}
+	}
+
+	values, err := getProperties(ctx, get, id, names)
+	if err != nil {
+		return nil, err
+	}
+
+	var written []string
+	for _, descriptor := range changes {
+		value, _ := descriptor.format(settings)
+
+		// Compare the normalized values, an unparsable current value is always overwritten
+		if descriptor.parse(current, values[descriptor.property]) == nil {
+			if currentValue, _ := descriptor.format(current); currentValue == value {
+				continue
+			}
+		}
+
+		err = set(ctx, id, descriptor.property, value)
+		if err != nil {
+			return written, err
+		}
+		written = append(written, descriptor.property)
+	}
+
+	return written, nil
+}
+
+func stringSetting(property string, field func(interface{}) **string) setting {
+	return setting{
+		property: property,
+		format: func(s interface{}) (string, bool) {
+			value := *field(s)
+			if value == nil {
+				return "", false
+			}
+			return *value, true
+		},
+		parse: func(s interface{}, value string) error {
+			*field(s) = &value
+			return nil
+		},
+	}
+}
+
+func boolSetting(property string, field func(interface{}) **bool) setting {
+	return setting{
+		property: property,
+		format: func(s interface{}) (string, bool) {
+			value := *field(s)
+			if value == nil {
+				return "", false
+			}
+			if *value {
+				return "1", true
+			}
+			return "0", true
+		},
+		parse: func(s interface{}, value string) error {
+			b := value == "1"
+			*field(s) = &b
+			return nil
+		},
+	}
+}
+
+func int64Setting(property string, field func(interface{}) **int64) setting {
+	return setting{
+		property: property,
+		format: func(s interface{}) (string, bool) {
+			value := *field(s)
+			if value == nil {
+				return "", false
+			}
+			return strconv.FormatInt(*value, 10), true
+		},
+		parse: func(s interface{}, value string) error {
+			var i int64
+			if value != "" {
+				var err error
+				i, err = strconv.ParseInt(value, 10, 64)
+				if err != nil {
+					return err
+				}
+			}
+			*field(s) = &i
+			return nil
+		},
+	}
+}
+
+func dependencyTypeSetting(property string, field func(interface{}) **DependencyType) setting {
+	return setting{
+		property: property,
+		format: func(s interface{}) (string, bool) {
+			value := *field(s)
+			if value == nil {
+				return "", false
+			}
+			return strconv.Itoa(int(*value)), true
+		},
+		parse: func(s interface{}, value string) error {
+			var t DependencyType
+			if value != "" {
+				i, err := strconv.Atoi(value)
+				if err != nil {
+					return err
+				}
+				t = DependencyType(i)
+			}
+			*field(s) = &t
+			return nil
+		},
+	}
+}
+
+// intervalSetting handles the scanning interval, which PRTG stores as "<seconds>|<label>", e.g. "60|60 seconds"
+func intervalSetting(property string, field func(interface{}) **time.Duration) setting {
+	return setting{
+		property: property,
+		format: func(s interface{}) (string, bool) {
+			value := *field(s)
+			if value == nil {
+				return "", false
+			}
+			return formatInterval(*value), true
+		},
+		parse: func(s interface{}, value string) error {
+			seconds := value
+			if i := strings.Index(value, "|"); i >= 0 {
+				seconds = value[:i]
+			}
+			var d time.Duration
+			if seconds != "" {
+				i, err := strconv.ParseInt(strings.TrimSpace(seconds), 10, 64)
+				if err != nil {
+					return err
+				}
+				d = time.Duration(i) * time.Second
+			}
+			*field(s) = &d
+			return nil
+		},
+	}
+}
+
+func formatInterval(d time.Duration) string {
+	seconds := int64(d / time.Second)
+
+	label := fmt.Sprintf("%d seconds", seconds)
+	switch {
+	case seconds >= 3600 && seconds%3600 == 0:
+		hours := seconds / 3600
+		label = fmt.Sprintf("%d hours", hours)
+		if hours == 1 {
+			label = "1 hour"
+		}
+	case seconds > 60 && seconds%60 == 0:
+		label = fmt.Sprintf("%d minutes", seconds/60)
+	}
+
+	return fmt.Sprintf("%d|%s", seconds, label)
+}
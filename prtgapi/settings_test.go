@@ -0,0 +1,132 @@
+package prtgapi
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDevicesService_GetSettings(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	newPropertyStore(t, mux, map[string]map[string]string{"1234": {
+		"name":               "testdevice",
+		"host":               "testdevice.example.com",
+		"location":           "Amsterdam",
+		"serviceurl":         "https://runbooks.example.com/testdevice",
+		"deviceicon":         "a_server_1.png",
+		"interval":           "300|5 minutes",
+		"intervalgroup":      "0",
+		"dependencytype":     "1",
+		"dependency":         "2001",
+		"scheduledependency": "1",
+		"accessgroup":        "1",
+	}})
+
+	ctx := context.Background()
+	got, err := client.Devices().GetSettings(ctx, 1234)
+	if err != nil {
+		t.Fatalf("Error while getting device settings: %v", err)
+	}
+
+	name, host, location, comments := "testdevice", "testdevice.example.com", "Amsterdam", ""
+	serviceURL, icon := "https://runbooks.example.com/testdevice", "a_server_1.png"
+	interval, inheritInterval := 5*time.Minute, false
+	dependencyType, dependencyID := DependencyObject, int64(2001)
+	inheritSchedule, inheritAccessRights := true, true
+	want := &DeviceSettings{
+		Name:                &name,
+		Host:                &host,
+		Location:            &location,
+		Comments:            &comments,
+		ServiceURL:          &serviceURL,
+		Icon:                &icon,
+		Interval:            &interval,
+		InheritInterval:     &inheritInterval,
+		DependencyType:      &dependencyType,
+		DependencyID:        &dependencyID,
+		InheritSchedule:     &inheritSchedule,
+		InheritAccessRights: &inheritAccessRights,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Got %+v, expected %+v", got, want)
+	}
+}
+
+func TestDevicesService_ApplySettings(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	store := newPropertyStore(t, mux, map[string]map[string]string{"1234": {
+		"location":      "Amsterdam",
+		"interval":      "60|60 seconds",
+		"intervalgroup": "1",
+	}})
+
+	location, interval, inheritInterval := "Rotterdam", time.Minute, false
+	ctx := context.Background()
+	got, err := client.Devices().ApplySettings(ctx, 1234, DeviceSettings{
+		Location:        &location,
+		Interval:        &interval,
+		InheritInterval: &inheritInterval,
+	})
+	if err != nil {
+		t.Fatalf("Error while applying device settings: %v", err)
+	}
+	if want := []string{"location", "intervalgroup"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected properties %v to be written, got %v", want, got)
+	}
+	if want, written := map[string]string{"location": "Rotterdam", "intervalgroup": "0"}, store.written()["1234"]; !reflect.DeepEqual(written, want) {
+		t.Errorf("Expected values %v to be written, got %v", want, written)
+	}
+}
+
+func TestDevicesService_ApplySettingsDependency(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	store := newPropertyStore(t, mux, map[string]map[string]string{
+		"1234": {"location": "Amsterdam", "dependencytype": "0", "depdelay": "30"},
+		"2001": {"dependencytype": "0"},
+	})
+	treeHandler(t, mux, map[string]string{
+		"0": `<group id="0"><name>Root</name>` +
+			`<device id="1234"><name>testdevice</name><sensor id="2345"><name>Ping</name></sensor></device>` +
+			`<device id="2000"><name>backend</name><sensor id="2001"><name>HTTP</name></sensor></device>` +
+			`</group>`,
+	})
+
+	ctx := context.Background()
+	dependencyType, dependencyID := DependencyObject, int64(2345)
+	_, err := client.Devices().ApplySettings(ctx, 1234, DeviceSettings{
+		DependencyType: &dependencyType,
+		DependencyID:   &dependencyID,
+	})
+	if !errors.Is(err, ErrCircularDependency) {
+		t.Errorf("Expected ErrCircularDependency, got %v", err)
+	}
+	if written := store.written(); len(written) > 0 {
+		t.Errorf("Expected nothing to be written, got %v", written)
+	}
+
+	dependencyID = 2001
+	got, err := client.Devices().ApplySettings(ctx, 1234, DeviceSettings{
+		DependencyType: &dependencyType,
+		DependencyID:   &dependencyID,
+	})
+	if err != nil {
+		t.Fatalf("Error while applying device settings: %v", err)
+	}
+	if want := []string{"dependencytype", "dependency"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected properties %v to be written, got %v", want, got)
+	}
+	want := map[string]map[string]string{
+		"1234": {"dependencytype": "1", "dependency": "2001", "depdelay": "30"},
+	}
+	if written := store.written(); !reflect.DeepEqual(written, want) {
+		t.Errorf("Expected %v to be written, got %v", want, written)
+	}
+}
@@ -0,0 +1,68 @@
+package prtgapi
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+const simulateSensorPath = "/api/simulate.htm"
+
+// SimulateError puts a sensor into a simulated error state until Resume is called
+func (s *SensorsService) SimulateError(ctx context.Context, id int64) error {
+	v := url.Values{}
+	v.Set("id", strconv.FormatInt(id, 10))
+	v.Set("action", "1")
+	return s.client.do(ctx, simulateSensorPath, v, nil)
+}
+
+// Resume ends a simulated error state of a sensor.
+// PRTG ends a simulated error the same way it resumes a paused sensor.
+func (s *SensorsService) Resume(ctx context.Context, id int64) error {
+	return s.Unpause(ctx, id)
+}
+
+// WithSimulatedError simulates an error on a sensor, waits until the sensor is down
+// and calls fn with the down sensor, e.g. to verify that notifications are sent.
+//
+// The sensor is always resumed afterwards, also when waiting or fn fails or ctx is done.
+// An error from fn takes precedence over an error while resuming.
+func (s *SensorsService) WithSimulatedError(ctx context.Context, id int64, options PollOptions, fn func(ctx context.Context, sensor *Sensor) error) (err error) {
+	err = s.SimulateError(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		resumeCtx, cancel := context.WithTimeout(context.Background(), cleanupTimeout)
+		defer cancel()
+
+		resumeErr := s.Resume(resumeCtx, id)
+		if resumeErr != nil && err == nil {
+			err = fmt.Errorf("Error while resuming sensor %d after simulating an error: %v", id, resumeErr)
+		}
+	}()
+
+	listOptions := SensorListOptions{
+		Columns: []string{"objid", "name", "status", "message", "lastcheck"},
+	}
+
+	var sensor *Sensor
+	err = poll(ctx, options, func(ctx context.Context) (bool, error) {
+		var err error
+		sensor, err = s.GetByID(ctx, id, listOptions)
+		if err != nil {
+			return false, err
+		}
+		if sensor == nil {
+			return false, fmt.Errorf("Sensor %d was not found while waiting for the simulated error", id)
+		}
+		return sensor.Status == StatusDown, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return fn(ctx, sensor)
+}
@@ -0,0 +1,84 @@
+package prtgapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSensorsService_WithSimulatedError(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	// Both the simulated error and resuming it use action 1, on different endpoints
+	var actions []string
+	action := func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testAuthentication(t, r)
+		testParams(t, r, map[string]string{
+			"id":     "2345",
+			"action": "1",
+		})
+		actions = append(actions, r.URL.Path)
+		w.Header().Add("Content-Type", "text/html; charset=UTF-8")
+		w.WriteHeader(200)
+		w.Write([]byte(`<HTML><BODY class="no-content"><B class="no-content">OK</B></BODY></HTML>`))
+	}
+	mux.HandleFunc("/api/simulate.htm", action)
+	mux.HandleFunc("/api/pause.htm", action)
+
+	polls := 0
+	mux.HandleFunc("/api/table.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testParams(t, r, map[string]string{
+			"content":      "sensors",
+			"filter_objid": "2345",
+		})
+		polls++
+		status, message := 3, "OK"
+		if polls > 1 {
+			status, message = 5, "Simulated error"
+		}
+		w.Header().Add("Content-Type", "application/json; charset=UTF-8")
+		w.WriteHeader(200)
+		fmt.Fprintf(w, `{"sensors": [{"objid": 2345, "name": "HTTP", "status_raw": %d, "message_raw": %q}]}`, status, message)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var down *Sensor
+	err := client.Sensors().WithSimulatedError(ctx, 2345, PollOptions{Interval: time.Millisecond}, func(ctx context.Context, sensor *Sensor) error {
+		if !reflect.DeepEqual(actions, []string{"/api/simulate.htm"}) {
+			t.Errorf("Expected the sensor to be in simulated error only, got actions %v", actions)
+		}
+		down = sensor
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Error while simulating error: %v", err)
+	}
+	if down == nil || down.Status != StatusDown || down.Message != "Simulated error" {
+		t.Errorf("Expected the callback to get the down sensor, got %+v", down)
+	}
+	if want := []string{"/api/simulate.htm", "/api/pause.htm"}; !reflect.DeepEqual(actions, want) {
+		t.Errorf("Expected actions %v, got %v", want, actions)
+	}
+
+	// The sensor is resumed when the callback fails
+	actions = nil
+	callbackErr := errors.New("notification not received")
+	err = client.Sensors().WithSimulatedError(ctx, 2345, PollOptions{Interval: time.Millisecond}, func(ctx context.Context, sensor *Sensor) error {
+		return callbackErr
+	})
+	if err != callbackErr {
+		t.Errorf("Expected the callback error, got %v", err)
+	}
+	if want := []string{"/api/simulate.htm", "/api/pause.htm"}; !reflect.DeepEqual(actions, want) {
+		t.Errorf("Expected actions %v, got %v", want, actions)
+	}
+}
@@ -0,0 +1,63 @@
+package prtgapi
+
+// Status represents the raw status of a PRTG object as returned in the status_raw column
+type Status int
+
+// The object statuses known to PRTG
+const (
+	StatusNone               Status = 0
+	StatusUnknown            Status = 1
+	StatusScanning           Status = 2
+	StatusUp                 Status = 3
+	StatusWarning            Status = 4
+	StatusDown               Status = 5
+	StatusNoProbe            Status = 6
+	StatusPausedByUser       Status = 7
+	StatusPausedByDependency Status = 8
+	StatusPausedBySchedule   Status = 9
+	StatusUnusual            Status = 10
+	StatusPausedByLicense    Status = 11
+	StatusPausedUntil        Status = 12
+	StatusDownAcknowledged   Status = 13
+	StatusDownPartial        Status = 14
+)
+
+// DownStatuses are the statuses of objects that are down, acknowledged or not
+var DownStatuses = []Status{StatusDown, StatusDownAcknowledged, StatusDownPartial}
+
+// PausedStatuses are the statuses of objects that are paused, for whatever reason
+var PausedStatuses = []Status{StatusPausedByUser, StatusPausedByDependency, StatusPausedBySchedule, StatusPausedByLicense, StatusPausedUntil}
+
+var statusNames = map[Status]string{
+	StatusNone:               "None",
+	StatusUnknown:            "Unknown",
+	StatusScanning:           "Scanning",
+	StatusUp:                 "Up",
+	StatusWarning:            "Warning",
+	StatusDown:               "Down",
+	StatusNoProbe:            "No Probe",
+	StatusPausedByUser:       "Paused by User",
+	StatusPausedByDependency: "Paused by Dependency",
+	StatusPausedBySchedule:   "Paused by Schedule",
+	StatusUnusual:            "Unusual",
+	StatusPausedByLicense:    "Paused by License",
+	StatusPausedUntil:        "Paused until",
+	StatusDownAcknowledged:   "Down (Acknowledged)",
+	StatusDownPartial:        "Down (Partial)",
+}
+
+func (s Status) String() string {
+	if name, ok := statusNames[s]; ok {
+		return name
+	}
+	return "Unknown"
+}
+
+// IsPaused returns whether the status is one of the paused statuses
+func (s Status) IsPaused() bool {
+	switch s {
+	case StatusPausedByUser, StatusPausedByDependency, StatusPausedBySchedule, StatusPausedByLicense, StatusPausedUntil:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,165 @@
+package prtgapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// ErrTagsModified is returned when the tags of an object changed between reading and writing them
+var ErrTagsModified = errors.New("The tags of the object were modified during the update")
+
+// TagUpdateOptions can be used to configure AddTags and RemoveTags
+//
+// ExpectedTags are the tags the caller based the update on, e.g. the Tags of a listed Device.
+// When set and the object has other tags by the time they are updated, ErrTagsModified is
+// returned and nothing is written. PRTG can't update tags atomically, so a change made
+// between reading the tags for the update and writing them is still not detected.
+type TagUpdateOptions struct {
+	ExpectedTags []string
+}
+
+// parseTags splits a PRTG tag string into the individual tags.
+// PRTG accepts both spaces and commas as separator.
+func parseTags(tags string) []string {
+	return strings.FieldsFunc(tags, isTagSeparator)
+}
+
+func isTagSeparator(r rune) bool {
+	return r == ',' || unicode.IsSpace(r)
+}
+
+// validateTags checks that every tag can be stored in PRTG as a single tag
+func validateTags(tags []string) error {
+	for _, tag := range tags {
+		if tag == "" {
+			return fmt.Errorf("Tags can't be empty")
+		}
+		if strings.IndexFunc(tag, func(r rune) bool { return isTagSeparator(r) || r == '"' }) >= 0 {
+			return fmt.Errorf("Tag %q contains a separator or quote, which is not allowed in PRTG tags", tag)
+		}
+	}
+	return nil
+}
+
+// mergeTags returns the tags followed by the additions that are not yet present
+func mergeTags(tags []string, additions []string) []string {
+	result := make([]string, 0, len(tags)+len(additions))
+	seen := map[string]bool{}
+	for _, tag := range append(append([]string{}, tags...), additions...) {
+		if !seen[tag] {
+			seen[tag] = true
+			result = append(result, tag)
+		}
+	}
+	return result
+}
+
+// withoutTags returns the tags minus the removals
+func withoutTags(tags []string, removals []string) []string {
+	remove := map[string]bool{}
+	for _, tag := range removals {
+		remove[tag] = true
+	}
+
+	result := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if !remove[tag] {
+			result = append(result, tag)
+		}
+	}
+	return result
+}
+
+func equalTags(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// updateTags reads the tags of an object, applies the update and writes them back when they changed
+func updateTags(ctx context.Context, get propertyGetter, set propertySetter, id int64, options TagUpdateOptions, update func([]string) []string) ([]string, error) {
+	current, err := get(ctx, id, "tags")
+	if err != nil {
+		return nil, err
+	}
+
+	tags := parseTags(current)
+	if options.ExpectedTags != nil && !equalTags(tags, options.ExpectedTags) {
+		return nil, ErrTagsModified
+	}
+
+	newTags := update(tags)
+	if equalTags(tags, newTags) {
+		return newTags, nil
+	}
+
+	err = set(ctx, id, "tags", strings.Join(newTags, ","))
+	if err != nil {
+		return nil, err
+	}
+
+	return newTags, nil
+}
+
+// AddTags adds tags to a device, keeping the tags it already has.
+// The resulting tags are returned.
+func (d *DevicesService) AddTags(ctx context.Context, id int64, tags []string, options TagUpdateOptions) ([]string, error) {
+	if err := validateTags(tags); err != nil {
+		return nil, err
+	}
+	return updateTags(ctx, d.GetProperty, d.UpdateProperty, id, options, func(current []string) []string {
+		return mergeTags(current, tags)
+	})
+}
+
+// RemoveTags removes tags from a device, keeping its other tags.
+// The resulting tags are returned.
+func (d *DevicesService) RemoveTags(ctx context.Context, id int64, tags []string, options TagUpdateOptions) ([]string, error) {
+	return updateTags(ctx, d.GetProperty, d.UpdateProperty, id, options, func(current []string) []string {
+		return withoutTags(current, tags)
+	})
+}
+
+// SetTags replaces all tags of a device
+func (d *DevicesService) SetTags(ctx context.Context, id int64, tags []string) error {
+	if err := validateTags(tags); err != nil {
+		return err
+	}
+	return d.UpdateProperty(ctx, id, "tags", strings.Join(mergeTags(nil, tags), ","))
+}
+
+// AddTags adds tags to a sensor, keeping the tags it already has.
+// The resulting tags are returned.
+func (s *SensorsService) AddTags(ctx context.Context, id int64, tags []string, options TagUpdateOptions) ([]string, error) {
+	if err := validateTags(tags); err != nil {
+		return nil, err
+	}
+	return updateTags(ctx, s.GetProperty, s.UpdateProperty, id, options, func(current []string) []string {
+		return mergeTags(current, tags)
+	})
+}
+
+// RemoveTags removes tags from a sensor, keeping its other tags.
+// The resulting tags are returned.
+func (s *SensorsService) RemoveTags(ctx context.Context, id int64, tags []string, options TagUpdateOptions) ([]string, error) {
+	return updateTags(ctx, s.GetProperty, s.UpdateProperty, id, options, func(current []string) []string {
+		return withoutTags(current, tags)
+	})
+}
+
+// SetTags replaces all tags of a sensor
+func (s *SensorsService) SetTags(ctx context.Context, id int64, tags []string) error {
+	if err := validateTags(tags); err != nil {
+		return err
+	}
+	return s.UpdateProperty(ctx, id, "tags", strings.Join(mergeTags(nil, tags), ","))
+}
@@ -0,0 +1,81 @@
+package prtgapi
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestDevicesService_AddTags(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	store := newPropertyStore(t, mux, map[string]map[string]string{
+		"1234": {"tags": "existing,k8s-ingress"},
+	})
+
+	ctx := context.Background()
+	got, err := client.Devices().AddTags(ctx, 1234, []string{"k8s-ingress", "new"}, TagUpdateOptions{})
+	if err != nil {
+		t.Fatalf("Error while adding tags: %v", err)
+	}
+	if want := []string{"existing", "k8s-ingress", "new"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Got %v, expected %v", got, want)
+	}
+	if want, written := "existing,k8s-ingress,new", store.written()["1234"]["tags"]; written != want {
+		t.Errorf("Expected tags %q to be written, got %q", want, written)
+	}
+
+	_, err = client.Devices().AddTags(ctx, 1234, []string{"with space"}, TagUpdateOptions{})
+	if err == nil {
+		t.Errorf("Expected an error when adding a tag with a space")
+	}
+}
+
+func TestSensorsService_RemoveTags(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	store := newPropertyStore(t, mux, map[string]map[string]string{
+		"1234": {"tags": "a b c"},
+	})
+
+	ctx := context.Background()
+	got, err := client.Sensors().RemoveTags(ctx, 1234, []string{"b"}, TagUpdateOptions{})
+	if err != nil {
+		t.Fatalf("Error while removing tags: %v", err)
+	}
+	if want := []string{"a", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Got %v, expected %v", got, want)
+	}
+	if want, written := "a,c", store.written()["1234"]["tags"]; written != want {
+		t.Errorf("Expected tags %q to be written, got %q", want, written)
+	}
+}
+
+func TestDevicesService_AddTagsConcurrentModification(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	// Someone else added a tag after the caller listed the device with tag a
+	store := newPropertyStore(t, mux, map[string]map[string]string{
+		"1234": {"tags": "a other"},
+	})
+
+	ctx := context.Background()
+	_, err := client.Devices().AddTags(ctx, 1234, []string{"b"}, TagUpdateOptions{ExpectedTags: []string{"a"}})
+	if err != ErrTagsModified {
+		t.Errorf("Expected ErrTagsModified, got %v", err)
+	}
+	if written := store.written(); len(written) > 0 {
+		t.Errorf("Expected no tags to be written, got %v", written)
+	}
+
+	got, err := client.Devices().AddTags(ctx, 1234, []string{"b"}, TagUpdateOptions{ExpectedTags: []string{"a", "other"}})
+	if err != nil {
+		t.Fatalf("Error while adding tags: %v", err)
+	}
+	if want := []string{"a", "other", "b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Got %v, expected %v", got, want)
+	}
+}
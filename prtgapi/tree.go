@@ -0,0 +1,222 @@
+package prtgapi
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const sensorTreePath = "/api/table.xml"
+
+// NodeKind is the kind of an object in the PRTG object tree
+type NodeKind string
+
+// The kinds of objects in the PRTG object tree
+const (
+	NodeGroup  NodeKind = "group"
+	NodeProbe  NodeKind = "probenode"
+	NodeDevice NodeKind = "device"
+	NodeSensor NodeKind = "sensor"
+)
+
+// SkipChildren can be returned by the function passed to Walk to skip the children of a node
+var SkipChildren = errors.New("skip children")
+
+// Node is an object in the PRTG object tree
+//
+// Host is only set for devices and SensorType only for sensors.
+// Parent is nil for the root of the tree.
+type Node struct {
+	ID         int64
+	Kind       NodeKind
+	Name       string
+	Status     Status
+	StatusText string
+	Tags       []string
+	Active     bool
+	Host       string
+	SensorType string
+
+	Parent   *Node
+	Children []*Node
+}
+
+// Tree returns the object tree below the object identified by rootID, use 0 for the whole installation.
+//
+// The response is decoded while it is read, so large installations don't have to fit in memory twice.
+func (client *Client) Tree(ctx context.Context, rootID int64) (*Node, error) {
+	v := url.Values{}
+	v.Set("content", "sensortree")
+	v.Set("id", strconv.FormatInt(rootID, 10))
+
+	res, err := client.request(ctx, sensorTreePath, v)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Got a non-200 response from PRTG. Status %d", res.StatusCode)
+	}
+
+	root, err := decodeTree(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("Error while decoding the object tree of object %d: %v", rootID, err)
+	}
+
+	return root, nil
+}
+
+func decodeTree(r io.Reader) (*Node, error) {
+	decoder := xml.NewDecoder(r)
+
+	var stack []*Node
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil, fmt.Errorf("No objects found")
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch element := token.(type) {
+		case xml.StartElement:
+			kind := NodeKind(element.Name.Local)
+			if isNodeKind(kind) {
+				node := &Node{Kind: kind}
+				for _, attr := range element.Attr {
+					switch attr.Name.Local {
+					case "id":
+						node.ID, _ = strconv.ParseInt(attr.Value, 10, 64)
+					case "active":
+						node.Active = attr.Value != "0"
+					}
+				}
+				if len(stack) > 0 {
+					node.Parent = stack[len(stack)-1]
+					node.Parent.Children = append(node.Parent.Children, node)
+				}
+				stack = append(stack, node)
+				continue
+			}
+
+			// Elements outside of the nodes, like the version, hold nothing of interest
+			if len(stack) == 0 {
+				continue
+			}
+
+			var value string
+			err = decoder.DecodeElement(&value, &element)
+			if err != nil {
+				return nil, err
+			}
+			setNodeField(stack[len(stack)-1], element.Name.Local, strings.TrimSpace(value))
+
+		case xml.EndElement:
+			if !isNodeKind(NodeKind(element.Name.Local)) || len(stack) == 0 {
+				continue
+			}
+			if len(stack) == 1 {
+				// The rest of the response holds no more objects
+				return stack[0], nil
+			}
+			stack = stack[:len(stack)-1]
+		}
+	}
+}
+
+func isNodeKind(kind NodeKind) bool {
+	switch kind {
+	case NodeGroup, NodeProbe, NodeDevice, NodeSensor:
+		return true
+	}
+	return false
+}
+
+func setNodeField(node *Node, name string, value string) {
+	switch name {
+	case "id":
+		if id, err := strconv.ParseInt(value, 10, 64); err == nil {
+			node.ID = id
+		}
+	case "name":
+		node.Name = value
+	case "status":
+		node.StatusText = value
+	case "status_raw":
+		if status, err := strconv.Atoi(value); err == nil {
+			node.Status = Status(status)
+		}
+	case "tags":
+		node.Tags = parseTags(value)
+	case "host":
+		node.Host = value
+	case "sensortype":
+		node.SensorType = value
+	}
+}
+
+// Walk calls fn for the node and all its descendants, parents before their children.
+//
+// When fn returns SkipChildren the children of that node are skipped, any other
+// error stops the walk and is returned.
+func (n *Node) Walk(fn func(node *Node) error) error {
+	err := fn(n)
+	if err == SkipChildren {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, child := range n.Children {
+		err = child.Walk(fn)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Find returns the node and descendants for which match returns true, in walk order
+func (n *Node) Find(match func(node *Node) bool) []*Node {
+	var found []*Node
+	n.Walk(func(node *Node) error {
+		if match(node) {
+			found = append(found, node)
+		}
+		return nil
+	})
+	return found
+}
+
+// FindByID returns the node or descendant with the given ID, or nil when there is none
+func (n *Node) FindByID(id int64) *Node {
+	var found *Node
+	errFound := errors.New("found")
+	n.Walk(func(node *Node) error {
+		if node.ID == id {
+			found = node
+			return errFound
+		}
+		return nil
+	})
+	return found
+}
+
+// Path returns the nodes from the root of the tree down to and including the node
+func (n *Node) Path() []*Node {
+	var path []*Node
+	for node := n; node != nil; node = node.Parent {
+		path = append([]*Node{node}, path...)
+	}
+	return path
+}
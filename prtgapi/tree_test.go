@@ -0,0 +1,136 @@
+package prtgapi
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+const sensorTreeXML = `<?xml version="1.0" encoding="UTF-8"?>
+<prtg>
+	<version>19.4.53.1912</version>
+	<sensortree>
+		<nodes>
+			<group id="0" noaccess="0" selected="0" active="-1">
+				<name>Root</name>
+				<id>0</id>
+				<tags></tags>
+				<status_raw>3</status_raw>
+				<status>Up</status>
+				<probenode id="1" noaccess="0" selected="0" active="-1">
+					<name>Local Probe</name>
+					<id>1</id>
+					<tags></tags>
+					<status_raw>5</status_raw>
+					<status>Down</status>
+					<group id="900" noaccess="0" selected="0" active="-1">
+						<name>Ingresses</name>
+						<id>900</id>
+						<tags>k8s</tags>
+						<status_raw>5</status_raw>
+						<status>Down</status>
+						<device id="1234" noaccess="0" selected="0" active="-1">
+							<name>www.example.com</name>
+							<id>1234</id>
+							<host>www.example.com</host>
+							<tags>k8s-ingress production</tags>
+							<status_raw>5</status_raw>
+							<status>Down</status>
+							<sensor id="2345" noaccess="0" selected="0" active="-1">
+								<name>HTTP</name>
+								<id>2345</id>
+								<sensortype>HTTP</sensortype>
+								<tags>httpsensor</tags>
+								<status_raw>5</status_raw>
+								<status>Down</status>
+							</sensor>
+							<sensor id="2346" noaccess="0" selected="0" active="0">
+								<name>Ping</name>
+								<id>2346</id>
+								<sensortype>Ping</sensortype>
+								<tags>pingsensor</tags>
+								<status_raw>7</status_raw>
+								<status>Paused by User</status>
+							</sensor>
+						</device>
+					</group>
+				</probenode>
+			</group>
+		</nodes>
+	</sensortree>
+</prtg>`
+
+func TestClient_Tree(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/api/table.xml", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testAuthentication(t, r)
+		testParams(t, r, map[string]string{
+			"content": "sensortree",
+			"id":      "0",
+		})
+		w.Header().Add("Content-Type", "text/xml; charset=UTF-8")
+		w.WriteHeader(200)
+		w.Write([]byte(sensorTreeXML))
+	})
+
+	ctx := context.Background()
+	root, err := client.Tree(ctx, 0)
+	if err != nil {
+		t.Fatalf("Error while getting the object tree: %v", err)
+	}
+
+	if root.Kind != NodeGroup || root.Name != "Root" || root.Parent != nil {
+		t.Errorf("Expected the root group as root, got %+v", root)
+	}
+
+	sensor := root.FindByID(2345)
+	if sensor == nil {
+		t.Fatalf("Expected to find sensor 2345")
+	}
+	if sensor.Kind != NodeSensor || sensor.SensorType != "HTTP" || sensor.Status != StatusDown || sensor.StatusText != "Down" || !sensor.Active {
+		t.Errorf("Unexpected sensor %+v", sensor)
+	}
+	if want := []string{"httpsensor"}; !reflect.DeepEqual(sensor.Tags, want) {
+		t.Errorf("Expected tags %v, got %v", want, sensor.Tags)
+	}
+
+	var names []string
+	for _, node := range sensor.Path() {
+		names = append(names, node.Name)
+	}
+	if want := []string{"Root", "Local Probe", "Ingresses", "www.example.com", "HTTP"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("Expected path %v, got %v", want, names)
+	}
+
+	device := sensor.Parent
+	if device.Host != "www.example.com" || !reflect.DeepEqual(device.Tags, []string{"k8s-ingress", "production"}) {
+		t.Errorf("Unexpected device %+v", device)
+	}
+
+	paused := root.Find(func(node *Node) bool {
+		return node.Status.IsPaused()
+	})
+	if len(paused) != 1 || paused[0].ID != 2346 || paused[0].Active {
+		t.Errorf("Expected only the inactive sensor 2346 to be paused, got %v", paused)
+	}
+
+	// The sensors are skipped when walking only down to the devices
+	var visited []int64
+	err = root.Walk(func(node *Node) error {
+		visited = append(visited, node.ID)
+		if node.Kind == NodeDevice {
+			return SkipChildren
+		}
+		return nil
+	})
+	if err != nil {
+		t.Errorf("Error while walking the tree: %v", err)
+	}
+	if want := []int64{0, 1, 900, 1234}; !reflect.DeepEqual(visited, want) {
+		t.Errorf("Expected to visit %v, got %v", want, visited)
+	}
+}
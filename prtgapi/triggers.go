@@ -0,0 +1,619 @@
+package prtgapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TriggersService handles communication with the notification trigger related methods of the PRTG API.
+// Triggers can be defined on any object: probes, groups, devices and sensors.
+type TriggersService service
+
+const (
+	triggerListPath              = "/api/table.json"
+	triggerEditPath              = "/editsettings"
+	triggerDeletePath            = "/api/deletesub.htm"
+	getTriggerObjectPropertyPath = "/api/getobjectproperty.htm"
+	setTriggerObjectPropertyPath = "/api/setobjectproperty.htm"
+)
+
+// NewTriggersService returns a new TriggersService for a client
+func NewTriggersService(client *Client) *TriggersService {
+	return &TriggersService{
+		client: client,
+	}
+}
+
+// TriggerType is the kind of a notification trigger
+type TriggerType string
+
+// The notification trigger types supported by PRTG
+const (
+	TriggerTypeState     TriggerType = "state"
+	TriggerTypeThreshold TriggerType = "threshold"
+	TriggerTypeChange    TriggerType = "change"
+	TriggerTypeSpeed     TriggerType = "speed"
+	TriggerTypeVolume    TriggerType = "volume"
+)
+
+// TriggerState is the state that fires a state trigger
+type TriggerState int
+
+// The states a state trigger can fire on
+const (
+	TriggerStateDown        TriggerState = 0
+	TriggerStateWarning     TriggerState = 1
+	TriggerStateUnusual     TriggerState = 2
+	TriggerStatePartialDown TriggerState = 3
+)
+
+var triggerStateNames = map[string]TriggerState{
+	"down":           TriggerStateDown,
+	"warning":        TriggerStateWarning,
+	"unusual":        TriggerStateUnusual,
+	"down (partial)": TriggerStatePartialDown,
+	"partial down":   TriggerStatePartialDown,
+}
+
+// TriggerCondition compares a channel value with the threshold of a threshold or speed trigger
+type TriggerCondition int
+
+// The conditions of threshold and speed triggers
+const (
+	TriggerAbove    TriggerCondition = 0
+	TriggerBelow    TriggerCondition = 1
+	TriggerEqual    TriggerCondition = 2
+	TriggerNotEqual TriggerCondition = 3
+)
+
+var triggerConditionNames = map[string]TriggerCondition{
+	"above":        TriggerAbove,
+	"below":        TriggerBelow,
+	"equal to":     TriggerEqual,
+	"not equal to": TriggerNotEqual,
+}
+
+// NotificationRef references a notification template by ID or by name.
+// When ID is 0 the template is looked up by Name.
+type NotificationRef struct {
+	ID   int64
+	Name string
+}
+
+// NoNotification can be used in a TriggerSpec to not send a notification
+var NoNotification = NotificationRef{ID: -1, Name: "None"}
+
+// Trigger is a notification trigger as read from PRTG
+//
+// ParentID is the object the trigger is defined on. When that differs from the
+// queried object ObjectID, the trigger is inherited and Inherited is set.
+// State is only set for state triggers and Condition for threshold and speed triggers.
+// Use Spec to edit a trigger based on its current settings.
+type Trigger struct {
+	ObjectID  int64
+	ParentID  int64
+	SubID     int64
+	Type      TriggerType
+	Inherited bool
+
+	State     *TriggerState
+	Channel   string
+	Condition *TriggerCondition
+	Threshold string
+	UnitSize  string
+	UnitTime  string
+	Period    string
+
+	Latency           time.Duration
+	EscalationLatency time.Duration
+	RepeatInterval    time.Duration
+
+	OnNotification         NotificationRef
+	OffNotification        NotificationRef
+	EscalationNotification NotificationRef
+}
+
+// TriggerSpec describes the settings of a notification trigger for Add and Edit.
+//
+// Type is required for Add. For Edit only the fields that are not nil are written.
+// Which fields apply depends on the trigger type, e.g. State only applies to state
+// triggers and Channel, Condition and Threshold to threshold triggers.
+// Latency and EscalationLatency are rounded to seconds, RepeatInterval to minutes.
+type TriggerSpec struct {
+	Type TriggerType
+
+	State     *TriggerState
+	Channel   *string
+	Condition *TriggerCondition
+	Threshold *string
+	UnitSize  *string
+	UnitTime  *string
+	Period    *string
+
+	Latency           *time.Duration
+	EscalationLatency *time.Duration
+	RepeatInterval    *time.Duration
+
+	OnNotification         *NotificationRef
+	OffNotification        *NotificationRef
+	EscalationNotification *NotificationRef
+}
+
+// NotificationTemplate is a notification template as listed by PRTG
+type NotificationTemplate struct {
+	ID   int64  `json:"objid"`
+	Name string `json:"name"`
+}
+
+type triggerRow struct {
+	Content json.RawMessage `json:"content"`
+}
+
+type triggerContent struct {
+	Type                   TriggerType `json:"type"`
+	SubID                  flexFloat   `json:"subid"`
+	ParentID               flexFloat   `json:"parentid"`
+	State                  string      `json:"nodest"`
+	Channel                string      `json:"channel"`
+	Condition              string      `json:"condition"`
+	Threshold              string      `json:"threshold"`
+	UnitSize               string      `json:"unitsize"`
+	UnitTime               string      `json:"unittime"`
+	Period                 string      `json:"period"`
+	Latency                flexFloat   `json:"latency"`
+	EscalationLatency      flexFloat   `json:"esclatency"`
+	RepeatInterval         flexFloat   `json:"repeatival"`
+	OnNotification         string      `json:"onnotificationid"`
+	OffNotification        string      `json:"offnotificationid"`
+	EscalationNotification string      `json:"escnotificationid"`
+}
+
+// List returns the notification triggers of an object, including the inherited ones
+func (t *TriggersService) List(ctx context.Context, objectID int64) ([]*Trigger, error) {
+	v := url.Values{}
+	v.Set("content", "triggers")
+	v.Set("columns", "content,objid")
+	v.Set("id", strconv.FormatInt(objectID, 10))
+
+	var triggerList struct {
+		Triggers []triggerRow `json:"triggers"`
+	}
+	err := t.client.do(ctx, triggerListPath, v, &triggerList)
+	if err != nil {
+		return nil, err
+	}
+
+	triggers := make([]*Trigger, 0, len(triggerList.Triggers))
+	for _, row := range triggerList.Triggers {
+		trigger, err := parseTrigger(objectID, row.Content)
+		if err != nil {
+			return nil, fmt.Errorf("Error while parsing the triggers of object %d: %v", objectID, err)
+		}
+		triggers = append(triggers, trigger)
+	}
+
+	return triggers, nil
+}
+
+// parseTrigger parses the content column of a trigger.
+// Depending on the PRTG version the content is a JSON object or a string holding one.
+func parseTrigger(objectID int64, raw json.RawMessage) (*Trigger, error) {
+	var encoded string
+	if err := json.Unmarshal(raw, &encoded); err == nil {
+		raw = json.RawMessage(encoded)
+	}
+
+	var content triggerContent
+	err := json.Unmarshal(raw, &content)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := parseTriggerState(content.State)
+	if err != nil {
+		return nil, err
+	}
+	condition, err := parseTriggerCondition(content.Condition)
+	if err != nil {
+		return nil, err
+	}
+
+	parentID := int64(content.ParentID.Value)
+	// PRTG uses parent ID 0 for triggers defined on the object itself
+	if parentID == 0 {
+		parentID = objectID
+	}
+
+	return &Trigger{
+		ObjectID:  objectID,
+		ParentID:  parentID,
+		SubID:     int64(content.SubID.Value),
+		Type:      content.Type,
+		Inherited: parentID != objectID,
+
+		State:     state,
+		Channel:   content.Channel,
+		Condition: condition,
+		Threshold: content.Threshold,
+		UnitSize:  content.UnitSize,
+		UnitTime:  content.UnitTime,
+		Period:    content.Period,
+
+		Latency:           content.Latency.seconds(),
+		EscalationLatency: content.EscalationLatency.seconds(),
+		RepeatInterval:    time.Duration(content.RepeatInterval.Value) * time.Minute,
+
+		OnNotification:         parseNotificationRef(content.OnNotification),
+		OffNotification:        parseNotificationRef(content.OffNotification),
+		EscalationNotification: parseNotificationRef(content.EscalationNotification),
+	}, nil
+}
+
+// parseTriggerState parses the state PRTG displays for a state trigger, e.g. "Down"
+func parseTriggerState(value string) (*TriggerState, error) {
+	if value == "" {
+		return nil, nil
+	}
+	state, ok := triggerStateNames[strings.ToLower(strings.TrimSpace(value))]
+	if !ok {
+		return nil, fmt.Errorf("Unknown trigger state %q", value)
+	}
+	return &state, nil
+}
+
+// parseTriggerCondition parses the condition PRTG displays for a threshold or speed trigger, e.g. "Above"
+func parseTriggerCondition(value string) (*TriggerCondition, error) {
+	if value == "" {
+		return nil, nil
+	}
+	condition, ok := triggerConditionNames[strings.ToLower(strings.TrimSpace(value))]
+	if !ok {
+		return nil, fmt.Errorf("Unknown trigger condition %q", value)
+	}
+	return &condition, nil
+}
+
+// Spec returns the settings of the trigger as a TriggerSpec, e.g. to edit some of them:
+//
+//	spec := trigger.Spec()
+//	spec.Latency = &latency
+//	err := client.Triggers().Edit(ctx, trigger.ParentID, trigger.SubID, spec)
+//
+// Only the fields that apply to the type of the trigger are set.
+func (t *Trigger) Spec() TriggerSpec {
+	spec := TriggerSpec{Type: t.Type}
+
+	optionalString := func(value string) *string {
+		if value == "" {
+			return nil
+		}
+		return &value
+	}
+	duration := func(d time.Duration) *time.Duration {
+		return &d
+	}
+	notification := func(ref NotificationRef) *NotificationRef {
+		if ref.ID == 0 {
+			return nil
+		}
+		return &ref
+	}
+
+	spec.OnNotification = notification(t.OnNotification)
+	switch t.Type {
+	case TriggerTypeState:
+		spec.Latency = duration(t.Latency)
+		spec.EscalationLatency = duration(t.EscalationLatency)
+		spec.RepeatInterval = duration(t.RepeatInterval)
+		spec.OffNotification = notification(t.OffNotification)
+		spec.EscalationNotification = notification(t.EscalationNotification)
+	case TriggerTypeThreshold, TriggerTypeSpeed:
+		spec.Latency = duration(t.Latency)
+		spec.OffNotification = notification(t.OffNotification)
+	}
+
+	if t.State != nil {
+		state := *t.State
+		spec.State = &state
+	}
+	if t.Condition != nil {
+		condition := *t.Condition
+		spec.Condition = &condition
+	}
+	spec.Channel = optionalString(t.Channel)
+	spec.Threshold = optionalString(t.Threshold)
+	spec.UnitSize = optionalString(t.UnitSize)
+	spec.UnitTime = optionalString(t.UnitTime)
+	spec.Period = optionalString(t.Period)
+
+	return spec
+}
+
+// parseNotificationRef parses a notification reference, which PRTG formats as "<id>|<name>"
+func parseNotificationRef(value string) NotificationRef {
+	id, name := value, ""
+	if i := strings.Index(value, "|"); i >= 0 {
+		id, name = value[:i], value[i+1:]
+	}
+	ref := NotificationRef{Name: name}
+	ref.ID, _ = strconv.ParseInt(strings.TrimSpace(id), 10, 64)
+	return ref
+}
+
+// Add adds a notification trigger to an object and returns the new trigger.
+//
+// PRTG doesn't return the sub ID of the new trigger, so it is found by comparing the triggers
+// of the object before and after adding it, keeping only those that match spec. The channel,
+// threshold and units are not compared, as PRTG lists them differently than they are set.
+// When another trigger that matches spec is added at the same time, an error is returned.
+func (t *TriggersService) Add(ctx context.Context, objectID int64, spec TriggerSpec) (*Trigger, error) {
+	if spec.Type == "" {
+		return nil, fmt.Errorf("A trigger type is required to add a trigger")
+	}
+
+	before, err := t.List(ctx, objectID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = t.edit(ctx, objectID, "new", spec)
+	if err != nil {
+		return nil, err
+	}
+
+	after, err := t.List(ctx, objectID)
+	if err != nil {
+		return nil, err
+	}
+
+	existing := map[int64]bool{}
+	for _, trigger := range before {
+		if !trigger.Inherited {
+			existing[trigger.SubID] = true
+		}
+	}
+
+	var added []*Trigger
+	for _, trigger := range after {
+		if !trigger.Inherited && !existing[trigger.SubID] && trigger.matches(spec) {
+			added = append(added, trigger)
+		}
+	}
+
+	switch len(added) {
+	case 0:
+		return nil, fmt.Errorf("PRTG accepted the %s trigger, but no new trigger was found on object %d", spec.Type, objectID)
+	case 1:
+		return added[0], nil
+	default:
+		return nil, fmt.Errorf("Found %d new %s triggers on object %d that match the added trigger", len(added), spec.Type, objectID)
+	}
+}
+
+// matches returns whether the trigger has the settings of spec that PRTG lists the way they are set
+func (t *Trigger) matches(spec TriggerSpec) bool {
+	if t.Type != spec.Type {
+		return false
+	}
+	if spec.State != nil && (t.State == nil || *t.State != *spec.State) {
+		return false
+	}
+	if spec.Condition != nil && (t.Condition == nil || *t.Condition != *spec.Condition) {
+		return false
+	}
+
+	durations := []struct {
+		spec      *time.Duration
+		trigger   time.Duration
+		precision time.Duration
+	}{
+		{spec.Latency, t.Latency, time.Second},
+		{spec.EscalationLatency, t.EscalationLatency, time.Second},
+		{spec.RepeatInterval, t.RepeatInterval, time.Minute},
+	}
+	for _, d := range durations {
+		if d.spec != nil && *d.spec/d.precision != d.trigger/d.precision {
+			return false
+		}
+	}
+
+	notifications := []struct {
+		spec    *NotificationRef
+		trigger NotificationRef
+	}{
+		{spec.OnNotification, t.OnNotification},
+		{spec.OffNotification, t.OffNotification},
+		{spec.EscalationNotification, t.EscalationNotification},
+	}
+	for _, n := range notifications {
+		if n.spec == nil {
+			continue
+		}
+		if n.spec.ID != 0 && n.spec.ID != n.trigger.ID {
+			return false
+		}
+		if n.spec.ID == 0 && n.spec.Name != n.trigger.Name {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Edit updates the fields of a trigger that are set in spec.
+// Inherited triggers can only be edited on the object they are defined on.
+func (t *TriggersService) Edit(ctx context.Context, objectID int64, subID int64, spec TriggerSpec) error {
+	return t.edit(ctx, objectID, strconv.FormatInt(subID, 10), spec)
+}
+
+func (t *TriggersService) edit(ctx context.Context, objectID int64, subID string, spec TriggerSpec) error {
+	fields, err := t.triggerFields(ctx, spec)
+	if err != nil {
+		return err
+	}
+
+	v := url.Values{}
+	// The fields of the trigger form end with the sub ID of the trigger
+	for name, value := range fields {
+		v.Set(name+"_"+subID, value)
+	}
+	v.Set("id", strconv.FormatInt(objectID, 10))
+	v.Set("subid", subID)
+	v.Set("objecttype", "nodetrigger")
+	if spec.Type != "" {
+		v.Set("class", string(spec.Type))
+	}
+
+	return t.client.do(ctx, triggerEditPath, v, &redirectResponse{})
+}
+
+func (t *TriggersService) triggerFields(ctx context.Context, spec TriggerSpec) (map[string]string, error) {
+	fields := map[string]string{}
+
+	if spec.State != nil {
+		fields["nodest"] = strconv.Itoa(int(*spec.State))
+	}
+	if spec.Condition != nil {
+		fields["condition"] = strconv.Itoa(int(*spec.Condition))
+	}
+
+	values := map[string]*string{
+		"channel":   spec.Channel,
+		"threshold": spec.Threshold,
+		"unitsize":  spec.UnitSize,
+		"unittime":  spec.UnitTime,
+		"period":    spec.Period,
+	}
+	for name, value := range values {
+		if value != nil {
+			fields[name] = *value
+		}
+	}
+
+	if spec.Latency != nil {
+		fields["latency"] = strconv.FormatInt(int64(*spec.Latency/time.Second), 10)
+	}
+	if spec.EscalationLatency != nil {
+		fields["esclatency"] = strconv.FormatInt(int64(*spec.EscalationLatency/time.Second), 10)
+	}
+	if spec.RepeatInterval != nil {
+		fields["repeatival"] = strconv.FormatInt(int64(*spec.RepeatInterval/time.Minute), 10)
+	}
+
+	notifications := map[string]*NotificationRef{
+		"onnotificationid":  spec.OnNotification,
+		"offnotificationid": spec.OffNotification,
+		"escnotificationid": spec.EscalationNotification,
+	}
+
+	var templates []*NotificationTemplate
+	for name, ref := range notifications {
+		if ref == nil {
+			continue
+		}
+
+		id := ref.ID
+		if id == 0 {
+			if templates == nil {
+				var err error
+				templates, err = t.Notifications(ctx)
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			var err error
+			id, err = resolveNotification(templates, ref.Name)
+			if err != nil {
+				return nil, err
+			}
+		}
+		fields[name] = strconv.FormatInt(id, 10)
+	}
+
+	return fields, nil
+}
+
+func resolveNotification(templates []*NotificationTemplate, name string) (int64, error) {
+	if name == "" {
+		return 0, fmt.Errorf("A notification template needs an ID or a name")
+	}
+
+	var found []*NotificationTemplate
+	for _, template := range templates {
+		if template.Name == name {
+			found = append(found, template)
+		}
+	}
+
+	switch len(found) {
+	case 0:
+		return 0, fmt.Errorf("Notification template %q was not found", name)
+	case 1:
+		return found[0].ID, nil
+	default:
+		return 0, fmt.Errorf("Found %d notification templates named %q, reference it by ID instead", len(found), name)
+	}
+}
+
+// Remove removes a trigger from the object it is defined on
+func (t *TriggersService) Remove(ctx context.Context, objectID int64, subID int64) error {
+	v := url.Values{}
+	v.Set("id", strconv.FormatInt(objectID, 10))
+	v.Set("subid", strconv.FormatInt(subID, 10))
+	return t.client.do(ctx, triggerDeletePath, v, nil)
+}
+
+// Notifications returns the notification templates that triggers can reference
+func (t *TriggersService) Notifications(ctx context.Context) ([]*NotificationTemplate, error) {
+	v := url.Values{}
+	v.Set("content", "notifications")
+	v.Set("columns", "objid,name")
+
+	var notificationList struct {
+		Notifications []*NotificationTemplate `json:"notifications"`
+	}
+	err := t.client.do(ctx, triggerListPath, v, &notificationList)
+	if err != nil {
+		return nil, err
+	}
+
+	return notificationList.Notifications, nil
+}
+
+// GetInheritance returns whether an object inherits the triggers of its parent
+func (t *TriggersService) GetInheritance(ctx context.Context, objectID int64) (bool, error) {
+	v := url.Values{}
+	v.Set("id", strconv.FormatInt(objectID, 10))
+	v.Set("name", "inherittriggers")
+
+	var propertyResult struct {
+		Value string `xml:"result"`
+	}
+
+	err := t.client.do(ctx, getTriggerObjectPropertyPath, v, &propertyResult)
+	if err != nil {
+		return false, err
+	}
+
+	return propertyResult.Value == "1", nil
+}
+
+// SetInheritance sets whether an object inherits the triggers of its parent
+func (t *TriggersService) SetInheritance(ctx context.Context, objectID int64, inherit bool) error {
+	value := "0"
+	if inherit {
+		value = "1"
+	}
+
+	v := url.Values{}
+	v.Set("id", strconv.FormatInt(objectID, 10))
+	v.Set("name", "inherittriggers")
+	v.Set("value", value)
+	return t.client.do(ctx, setTriggerObjectPropertyPath, v, nil)
+}
@@ -0,0 +1,277 @@
+package prtgapi
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTriggersService_List(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/api/table.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testAuthentication(t, r)
+		testParams(t, r, map[string]string{
+			"content": "triggers",
+			"columns": "content,objid",
+			"id":      "2345",
+		})
+		w.Header().Add("Content-Type", "application/json; charset=UTF-8")
+		w.WriteHeader(200)
+		w.Write([]byte(`{"triggers": [
+			{"objid": 2345, "content": "{\"type\":\"state\",\"subid\":\"1\",\"parentid\":\"0\",\"nodest\":\"Down\",\"latency\":\"60\",\"onnotificationid\":\"300|Email to admin\",\"offnotificationid\":\"-1|None\",\"escnotificationid\":\"-1|None\",\"esclatency\":\"300\",\"repeatival\":\"10\"}"},
+			{"objid": 2345, "content": {"type": "threshold", "subid": 2, "parentid": 900, "channel": "Response Time", "condition": "Above", "threshold": "500", "latency": 0, "onnotificationid": "301|Slack"}}
+		]}`))
+	})
+
+	ctx := context.Background()
+	got, err := client.Triggers().List(ctx, 2345)
+	if err != nil {
+		t.Fatalf("Error while listing triggers: %v", err)
+	}
+
+	down, above := TriggerStateDown, TriggerAbove
+	want := []*Trigger{
+		&Trigger{
+			ObjectID:               2345,
+			ParentID:               2345,
+			SubID:                  1,
+			Type:                   TriggerTypeState,
+			State:                  &down,
+			Latency:                time.Minute,
+			EscalationLatency:      5 * time.Minute,
+			RepeatInterval:         10 * time.Minute,
+			OnNotification:         NotificationRef{ID: 300, Name: "Email to admin"},
+			OffNotification:        NoNotification,
+			EscalationNotification: NoNotification,
+		},
+		&Trigger{
+			ObjectID:       2345,
+			ParentID:       900,
+			SubID:          2,
+			Type:           TriggerTypeThreshold,
+			Inherited:      true,
+			Channel:        "Response Time",
+			Condition:      &above,
+			Threshold:      "500",
+			OnNotification: NotificationRef{ID: 301, Name: "Slack"},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Got %+v and %+v, expected %+v and %+v", got[0], got[1], want[0], want[1])
+	}
+}
+
+func TestTriggersService_Add(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	added := false
+	mux.HandleFunc("/api/table.json", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testAuthentication(t, r)
+		w.Header().Add("Content-Type", "application/json; charset=UTF-8")
+		w.WriteHeader(200)
+
+		switch r.URL.Query().Get("content") {
+		case "notifications":
+			w.Write([]byte(`{"notifications": [{"objid": 300, "name": "Email to admin"}, {"objid": 301, "name": "Slack"}]}`))
+		case "triggers":
+			if !added {
+				w.Write([]byte(`{"triggers": [{"objid": 2345, "content": {"type": "change", "subid": 1, "parentid": 0, "onnotificationid": "300|Email to admin"}}]}`))
+				return
+			}
+			// Another client added a change trigger at the same time
+			w.Write([]byte(`{"triggers": [{"objid": 2345, "content": {"type": "change", "subid": 1, "parentid": 0, "onnotificationid": "300|Email to admin"}}, {"objid": 2345, "content": {"type": "state", "subid": 2, "parentid": 0, "nodest": "Warning", "latency": 120, "onnotificationid": "301|Slack"}}, {"objid": 2345, "content": {"type": "change", "subid": 3, "parentid": 0, "onnotificationid": "301|Slack"}}]}`))
+		default:
+			t.Errorf("Unexpected content %q", r.URL.Query().Get("content"))
+		}
+	})
+
+	mux.HandleFunc("/editsettings", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testAuthentication(t, r)
+		testParams(t, r, map[string]string{
+			"id":                   "2345",
+			"subid":                "new",
+			"objecttype":           "nodetrigger",
+			"class":                "state",
+			"nodest_new":           "1",
+			"latency_new":          "120",
+			"onnotificationid_new": "301",
+		})
+		added = true
+		w.Header().Add("Location", "/sensor.htm?id=2345&tabid=6")
+		w.WriteHeader(302)
+	})
+
+	state := TriggerStateWarning
+	latency := 2 * time.Minute
+	ctx := context.Background()
+	got, err := client.Triggers().Add(ctx, 2345, TriggerSpec{
+		Type:           TriggerTypeState,
+		State:          &state,
+		Latency:        &latency,
+		OnNotification: &NotificationRef{Name: "Slack"},
+	})
+	if err != nil {
+		t.Fatalf("Error while adding trigger: %v", err)
+	}
+	if got.SubID != 2 || got.State == nil || *got.State != TriggerStateWarning || got.OnNotification.ID != 301 {
+		t.Errorf("Expected the new state trigger with sub ID 2, got %+v", got)
+	}
+
+	_, err = client.Triggers().Add(ctx, 2345, TriggerSpec{
+		Type:           TriggerTypeChange,
+		OnNotification: &NotificationRef{Name: "Pager"},
+	})
+	if err == nil {
+		t.Errorf("Expected an error for an unknown notification template")
+	}
+}
+
+func TestTriggersService_AddAmbiguous(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	added := false
+	mux.HandleFunc("/api/table.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json; charset=UTF-8")
+		w.WriteHeader(200)
+		if !added {
+			w.Write([]byte(`{"triggers": []}`))
+			return
+		}
+		// Another client added the same trigger at the same time
+		w.Write([]byte(`{"triggers": [{"objid": 2345, "content": {"type": "change", "subid": 1, "parentid": 0, "onnotificationid": "300|Email to admin"}}, {"objid": 2345, "content": {"type": "change", "subid": 2, "parentid": 0, "onnotificationid": "300|Email to admin"}}]}`))
+	})
+	mux.HandleFunc("/editsettings", func(w http.ResponseWriter, r *http.Request) {
+		added = true
+		w.Header().Add("Location", "/sensor.htm?id=2345&tabid=6")
+		w.WriteHeader(302)
+	})
+
+	ctx := context.Background()
+	_, err := client.Triggers().Add(ctx, 2345, TriggerSpec{
+		Type:           TriggerTypeChange,
+		OnNotification: &NotificationRef{ID: 300},
+	})
+	if err == nil {
+		t.Errorf("Expected an error when more than one new trigger matches")
+	}
+}
+
+func TestTriggersService_EditListed(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/api/table.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json; charset=UTF-8")
+		w.WriteHeader(200)
+		w.Write([]byte(`{"triggers": [
+			{"objid": 2345, "content": {"type": "state", "subid": 1, "parentid": 0, "nodest": "Down", "latency": 60, "onnotificationid": "300|Email to admin", "offnotificationid": "-1|None", "escnotificationid": "-1|None", "esclatency": 300, "repeatival": 10}},
+			{"objid": 2345, "content": {"type": "threshold", "subid": 2, "parentid": 0, "channel": "Response Time", "condition": "Below", "threshold": "500", "latency": 0, "onnotificationid": "301|Slack", "offnotificationid": "-1|None"}}
+		]}`))
+	})
+
+	var got []map[string]string
+	mux.HandleFunc("/editsettings", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testAuthentication(t, r)
+		params := map[string]string{}
+		for name := range r.URL.Query() {
+			if name != "username" && name != "passhash" {
+				params[name] = r.URL.Query().Get(name)
+			}
+		}
+		got = append(got, params)
+		w.Header().Add("Location", "/sensor.htm?id=2345&tabid=6")
+		w.WriteHeader(302)
+	})
+
+	ctx := context.Background()
+	triggers, err := client.Triggers().List(ctx, 2345)
+	if err != nil {
+		t.Fatalf("Error while listing triggers: %v", err)
+	}
+	for _, trigger := range triggers {
+		err = client.Triggers().Edit(ctx, trigger.ParentID, trigger.SubID, trigger.Spec())
+		if err != nil {
+			t.Fatalf("Error while editing trigger %d: %v", trigger.SubID, err)
+		}
+	}
+
+	want := []map[string]string{
+		{
+			"id":                  "2345",
+			"subid":               "1",
+			"objecttype":          "nodetrigger",
+			"class":               "state",
+			"nodest_1":            "0",
+			"latency_1":           "60",
+			"esclatency_1":        "300",
+			"repeatival_1":        "10",
+			"onnotificationid_1":  "300",
+			"offnotificationid_1": "-1",
+			"escnotificationid_1": "-1",
+		},
+		{
+			"id":                  "2345",
+			"subid":               "2",
+			"objecttype":          "nodetrigger",
+			"class":               "threshold",
+			"channel_2":           "Response Time",
+			"condition_2":         "1",
+			"threshold_2":         "500",
+			"latency_2":           "0",
+			"onnotificationid_2":  "301",
+			"offnotificationid_2": "-1",
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Got %v, expected %v", got, want)
+	}
+}
+
+func TestTriggersService_Remove(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/api/deletesub.htm", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testAuthentication(t, r)
+		testParams(t, r, map[string]string{
+			"id":    "2345",
+			"subid": "2",
+		})
+		w.Header().Add("Content-Type", "text/html; charset=UTF-8")
+		w.WriteHeader(200)
+		w.Write([]byte(`<HTML><BODY class="no-content"><B class="no-content">OK</B></BODY></HTML>`))
+	})
+
+	ctx := context.Background()
+	err := client.Triggers().Remove(ctx, 2345, 2)
+	if err != nil {
+		t.Errorf("Error while removing trigger: %v", err)
+	}
+}
+
+func TestTriggersService_SetInheritance(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	store := newPropertyStore(t, mux, nil)
+
+	ctx := context.Background()
+	err := client.Triggers().SetInheritance(ctx, 2345, false)
+	if err != nil {
+		t.Errorf("Error while disabling trigger inheritance: %v", err)
+	}
+	if value := store.written()["2345"]["inherittriggers"]; value != "0" {
+		t.Errorf("Expected inherittriggers to be set to 0, got %q", value)
+	}
+}
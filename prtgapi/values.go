@@ -0,0 +1,58 @@
+package prtgapi
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// flexFloat decodes raw numeric values from PRTG tables.
+// PRTG returns these as a JSON number, as a string, or as an empty string when there is no value.
+type flexFloat struct {
+	Value float64
+	Valid bool
+}
+
+func (f *flexFloat) UnmarshalJSON(data []byte) error {
+	raw := strings.TrimSpace(string(data))
+	if unquoted, err := strconv.Unquote(raw); err == nil {
+		raw = strings.TrimSpace(unquoted)
+	}
+
+	if raw == "" || raw == "null" {
+		*f = flexFloat{}
+		return nil
+	}
+
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return err
+	}
+
+	*f = flexFloat{Value: value, Valid: true}
+	return nil
+}
+
+// oleEpoch is the start of the OLE automation dates that PRTG uses for raw date values
+var oleEpoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+// oleDateToTime converts an OLE automation date (days since oleEpoch) to a time.
+// PRTG returns the raw dates in UTC, unlike the formatted dates next to them and the dates
+// it accepts in requests, which are in the timezone of the core server (see Client.Location).
+func oleDateToTime(days float64) time.Time {
+	return oleEpoch.Add(time.Duration(math.Round(days*24*60*60*1000)) * time.Millisecond)
+}
+
+// time returns the value as a time when it holds a valid OLE automation date
+func (f flexFloat) time() time.Time {
+	if !f.Valid || f.Value == 0 {
+		return time.Time{}
+	}
+	return oleDateToTime(f.Value)
+}
+
+// seconds returns the value as a duration when it holds a number of seconds
+func (f flexFloat) seconds() time.Duration {
+	return time.Duration(math.Round(f.Value * float64(time.Second)))
+}
@@ -0,0 +1,29 @@
+/*
+Package prtgpush pushes results to HTTP Push Data Advanced sensors in PRTG
+
+An HTTP Push Data Advanced sensor listens on a port of its probe (5050 by default)
+for results sent with the token that is configured on the sensor. A result holds
+one or more channels, each with a value, unit and optional limits, and a text
+message. Results can be sent as JSON or XML, with a GET or POST request.
+
+Sample usage
+
+	pusher := &prtgpush.Pusher{
+		URL:   "http://probe.example.com:5050",
+		Token: "batch-import",
+	}
+
+	err := pusher.Push(ctx, prtgpush.Result{
+		Channels: []prtgpush.Channel{
+			{Name: "Imported records", Value: 1250, Unit: prtgpush.UnitCount},
+			{Name: "Duration", Value: 12.5, Float: true, Unit: prtgpush.UnitTimeSeconds},
+		},
+		Text: "Import finished",
+	})
+	if err != nil {
+		log.Fatalf("Unable to push the result to PRTG: %v", err)
+	}
+
+Failed pushes are retried with a growing delay, unless PRTG rejected the result.
+*/
+package prtgpush
@@ -0,0 +1,213 @@
+package prtgpush
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	defaultRetries    = 3
+	defaultRetryDelay = time.Second
+)
+
+// Method is the HTTP method used to push results
+type Method string
+
+// The HTTP methods HTTP Push Data Advanced sensors accept
+const (
+	MethodPOST Method = http.MethodPost
+	MethodGET  Method = http.MethodGet
+)
+
+// Format is the encoding used to push results
+type Format string
+
+// The encodings HTTP Push Data Advanced sensors accept
+const (
+	FormatJSON Format = "json"
+	FormatXML  Format = "xml"
+)
+
+// ErrNoMatchingSensor is returned when the probe accepted the result,
+// but no sensor is configured with the token
+var ErrNoMatchingSensor = errors.New("No sensor matches the push token")
+
+// Pusher pushes results to the HTTP Push Data Advanced sensors on a probe
+//
+// URL is the base URL of the probe, e.g. "http://probe.example.com:5050" and Token is the
+// token configured on the sensor. Method defaults to POST and Format to JSON. With GET the
+// encoded result is sent in the content query parameter, which limits the size of results.
+//
+// Failed pushes are retried Retries times (default 3, use a negative value to disable retries),
+// the delay starts at RetryDelay (default 1 second) and doubles after every retry.
+// Results rejected by the probe are not retried.
+type Pusher struct {
+	URL        string
+	Token      string
+	Method     Method
+	Format     Format
+	HTTPClient *http.Client
+
+	Retries    int
+	RetryDelay time.Duration
+}
+
+// pushError is an error of a single push, retry tells whether pushing again may help
+type pushError struct {
+	err   error
+	retry bool
+}
+
+func (e *pushError) Error() string {
+	return e.err.Error()
+}
+
+// Push sends a result to the sensors with the token of the pusher
+func (p *Pusher) Push(ctx context.Context, result Result) error {
+	if p.Token == "" {
+		return fmt.Errorf("A token is required to push results")
+	}
+
+	err := result.Validate()
+	if err != nil {
+		return err
+	}
+
+	body, contentType, err := p.encode(result)
+	if err != nil {
+		return err
+	}
+
+	retries := p.Retries
+	if retries == 0 {
+		retries = defaultRetries
+	}
+	delay := p.RetryDelay
+	if delay <= 0 {
+		delay = defaultRetryDelay
+	}
+
+	for attempt := 0; ; attempt++ {
+		err = p.send(ctx, body, contentType)
+		if err == nil {
+			return nil
+		}
+
+		pushErr, ok := err.(*pushError)
+		if !ok {
+			return err
+		}
+		if !pushErr.retry || attempt >= retries {
+			return fmt.Errorf("Error while pushing result to PRTG after %d attempt(s): %w", attempt+1, pushErr.err)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("Error while pushing result to PRTG: %v, giving up: %w", pushErr.err, ctx.Err())
+		case <-timer.C:
+		}
+		delay *= 2
+	}
+}
+
+func (p *Pusher) encode(result Result) ([]byte, string, error) {
+	switch p.Format {
+	case "", FormatJSON:
+		body, err := encodeJSON(result)
+		return body, "application/json", err
+	case FormatXML:
+		body, err := encodeXML(result)
+		return body, "application/xml", err
+	default:
+		return nil, "", fmt.Errorf("Unknown push format %q", p.Format)
+	}
+}
+
+// send makes a single attempt to push an encoded result
+func (p *Pusher) send(ctx context.Context, body []byte, contentType string) error {
+	u, err := url.Parse(p.URL)
+	if err != nil {
+		return fmt.Errorf("Invalid probe URL %q: %v", p.URL, err)
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/" + p.Token
+
+	var req *http.Request
+	switch p.Method {
+	case "", MethodPOST:
+		req, err = http.NewRequestWithContext(ctx, http.MethodPost, u.String(), bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", contentType)
+	case MethodGET:
+		v := url.Values{}
+		v.Set("content", string(body))
+		u.RawQuery = v.Encode()
+		req, err = http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+		if err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("Unknown push method %q", p.Method)
+	}
+
+	httpClient := p.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	res, err := httpClient.Do(req)
+	if err != nil {
+		// The token is part of the URL, keep it out of the error
+		if urlErr, ok := err.(*url.Error); ok {
+			err = urlErr.Err
+		}
+		return &pushError{err: err, retry: ctx.Err() == nil}
+	}
+	defer res.Body.Close()
+
+	resBody, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return &pushError{err: err, retry: true}
+	}
+
+	if res.StatusCode >= 500 {
+		return &pushError{err: fmt.Errorf("Got a %d response from the probe: %s", res.StatusCode, strings.TrimSpace(string(resBody))), retry: true}
+	}
+	if res.StatusCode != http.StatusOK {
+		return &pushError{err: fmt.Errorf("The probe rejected the result with status %d: %s", res.StatusCode, strings.TrimSpace(string(resBody)))}
+	}
+
+	return checkResponse(resBody)
+}
+
+// checkResponse checks the answer of the probe, e.g. {"status": "Ok", "Matching Sensors": "1"}
+func checkResponse(body []byte) error {
+	var response struct {
+		Status          string      `json:"status"`
+		MatchingSensors interface{} `json:"Matching Sensors"`
+	}
+	// Older probes answer without a body, a 200 response is all there is to check then
+	if json.Unmarshal(body, &response) != nil {
+		return nil
+	}
+
+	if response.Status != "" && !strings.EqualFold(response.Status, "Ok") {
+		return &pushError{err: fmt.Errorf("The probe rejected the result: %s", response.Status)}
+	}
+	if response.MatchingSensors != nil && fmt.Sprint(response.MatchingSensors) == "0" {
+		return &pushError{err: ErrNoMatchingSensor}
+	}
+
+	return nil
+}
@@ -0,0 +1,218 @@
+package prtgpush
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// probe is an httptest stand-in for a probe with HTTP Push Data Advanced sensors
+type probe struct {
+	server   *httptest.Server
+	requests []*http.Request
+	bodies   []string
+	// failures is the number of requests that are answered with a 500 response
+	failures int
+	// matching is the number of sensors that match the pushed token
+	matching string
+}
+
+func newProbe(t *testing.T) *probe {
+	p := &probe{matching: "1"}
+	p.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Fatalf("Error while reading request body: %v", err)
+		}
+		p.requests = append(p.requests, r)
+		p.bodies = append(p.bodies, string(body))
+
+		if r.URL.Path != "/batch-import" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if len(p.requests) <= p.failures {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"status": "Ok", "Matching Sensors": "` + p.matching + `"}`))
+	}))
+	return p
+}
+
+func float(f float64) *float64 {
+	return &f
+}
+
+var testResult = Result{
+	Channels: []Channel{
+		{Name: "Imported records", Value: 1250, Unit: UnitCount},
+		{
+			Name:   "Duration",
+			Value:  12.5,
+			Float:  true,
+			Unit:   UnitTimeSeconds,
+			Limits: &Limits{MaxWarning: float(60), MaxError: float(300), ErrorMessage: "Import too slow"},
+		},
+	},
+	Text: "Import finished",
+}
+
+func TestPusher_Push_JSON(t *testing.T) {
+	probe := newProbe(t)
+	defer probe.server.Close()
+
+	pusher := &Pusher{URL: probe.server.URL, Token: "batch-import"}
+	err := pusher.Push(context.Background(), testResult)
+	if err != nil {
+		t.Fatalf("Error while pushing result: %v", err)
+	}
+
+	if len(probe.requests) != 1 {
+		t.Fatalf("Expected 1 request, got %d", len(probe.requests))
+	}
+	r := probe.requests[0]
+	if r.Method != "POST" || r.Header.Get("Content-Type") != "application/json" {
+		t.Errorf("Expected a JSON POST, got %s with content type %s", r.Method, r.Header.Get("Content-Type"))
+	}
+
+	var got map[string]interface{}
+	err = json.Unmarshal([]byte(probe.bodies[0]), &got)
+	if err != nil {
+		t.Fatalf("Error while decoding pushed result: %v", err)
+	}
+	want := map[string]interface{}{
+		"prtg": map[string]interface{}{
+			"text": "Import finished",
+			"result": []interface{}{
+				map[string]interface{}{"channel": "Imported records", "value": 1250.0, "unit": "Count"},
+				map[string]interface{}{
+					"channel":         "Duration",
+					"value":           12.5,
+					"float":           1.0,
+					"unit":            "TimeSeconds",
+					"limitmode":       1.0,
+					"limitmaxwarning": "60",
+					"limitmaxerror":   "300",
+					"limiterrormsg":   "Import too slow",
+				},
+			},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Got %v, expected %v", got, want)
+	}
+}
+
+func TestPusher_Push_XMLWithGET(t *testing.T) {
+	probe := newProbe(t)
+	defer probe.server.Close()
+
+	pusher := &Pusher{URL: probe.server.URL + "/", Token: "batch-import", Method: MethodGET, Format: FormatXML}
+	err := pusher.Push(context.Background(), Result{Error: true, Text: "Import failed"})
+	if err != nil {
+		t.Fatalf("Error while pushing result: %v", err)
+	}
+
+	r := probe.requests[0]
+	if r.Method != "GET" {
+		t.Errorf("Expected a GET request, got %s", r.Method)
+	}
+
+	var got struct {
+		Text  string `xml:"text"`
+		Error int    `xml:"error"`
+	}
+	err = xml.Unmarshal([]byte(r.URL.Query().Get("content")), &got)
+	if err != nil {
+		t.Fatalf("Error while decoding pushed result: %v", err)
+	}
+	if got.Text != "Import failed" || got.Error != 1 {
+		t.Errorf("Expected an error result with text 'Import failed', got %+v", got)
+	}
+}
+
+func TestPusher_Push_Retries(t *testing.T) {
+	probe := newProbe(t)
+	defer probe.server.Close()
+	probe.failures = 2
+
+	pusher := &Pusher{URL: probe.server.URL, Token: "batch-import", RetryDelay: time.Millisecond}
+	err := pusher.Push(context.Background(), testResult)
+	if err != nil {
+		t.Fatalf("Error while pushing result: %v", err)
+	}
+	if len(probe.requests) != 3 {
+		t.Errorf("Expected 3 requests, got %d", len(probe.requests))
+	}
+
+	// Without retries the first failure is returned
+	probe.requests = nil
+	pusher.Retries = -1
+	err = pusher.Push(context.Background(), testResult)
+	if err == nil {
+		t.Errorf("Expected an error without retries")
+	}
+	if len(probe.requests) != 1 {
+		t.Errorf("Expected 1 request, got %d", len(probe.requests))
+	}
+}
+
+func TestPusher_Push_Rejected(t *testing.T) {
+	probe := newProbe(t)
+	defer probe.server.Close()
+
+	// Rejected results are not retried
+	pusher := &Pusher{URL: probe.server.URL, Token: "unknown", RetryDelay: time.Millisecond}
+	err := pusher.Push(context.Background(), testResult)
+	if err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("Expected a 404 error, got %v", err)
+	}
+	if len(probe.requests) != 1 {
+		t.Errorf("Expected 1 request, got %d", len(probe.requests))
+	}
+
+	probe.matching = "0"
+	pusher.Token = "batch-import"
+	err = pusher.Push(context.Background(), testResult)
+	if !errors.Is(err, ErrNoMatchingSensor) {
+		t.Errorf("Expected ErrNoMatchingSensor, got %v", err)
+	}
+}
+
+func TestResult_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		result Result
+	}{
+		{"no channels", Result{Text: "Nothing"}},
+		{"unnamed channel", Result{Channels: []Channel{{Value: 1}}}},
+		{"duplicate channel", Result{Channels: []Channel{{Name: "Count", Value: 1}, {Name: "Count", Value: 2}}}},
+		{"fraction without float", Result{Channels: []Channel{{Name: "Duration", Value: 1.5}}}},
+		{"min above max", Result{Channels: []Channel{{Name: "Count", Value: 1, Limits: &Limits{MinError: float(10), MaxWarning: float(5)}}}}},
+		{"min error above min warning", Result{Channels: []Channel{{Name: "Count", Value: 1, Limits: &Limits{MinError: float(10), MinWarning: float(5)}}}}},
+		{"max warning above max error", Result{Channels: []Channel{{Name: "Count", Value: 1, Limits: &Limits{MaxWarning: float(10), MaxError: float(5)}}}}},
+		{"long text", Result{Channels: []Channel{{Name: "Count", Value: 1}}, Text: strings.Repeat("a", 2001)}},
+	}
+
+	for _, tt := range tests {
+		if err := tt.result.Validate(); err == nil {
+			t.Errorf("Expected an error for a result with %s", tt.name)
+		}
+	}
+
+	// The length of the text is counted in characters, not bytes
+	result := Result{Channels: []Channel{{Name: "Count", Value: 1}}, Text: strings.Repeat("é", 2000)}
+	if err := result.Validate(); err != nil {
+		t.Errorf("Expected a text of 2000 characters to be valid, got %v", err)
+	}
+}
@@ -0,0 +1,210 @@
+package prtgpush
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"unicode/utf8"
+)
+
+// maxTextLength is the maximum length of the text message PRTG accepts
+const maxTextLength = 2000
+
+// Unit is the unit of the value of a channel
+type Unit string
+
+// The units supported by HTTP Push Data Advanced sensors
+const (
+	UnitBytesBandwidth Unit = "BytesBandwidth"
+	UnitBytesMemory    Unit = "BytesMemory"
+	UnitBytesDisk      Unit = "BytesDisk"
+	UnitBytesFile      Unit = "BytesFile"
+	UnitTemperature    Unit = "Temperature"
+	UnitPercent        Unit = "Percent"
+	UnitTimeResponse   Unit = "TimeResponse"
+	UnitTimeSeconds    Unit = "TimeSeconds"
+	UnitTimeHours      Unit = "TimeHours"
+	UnitCount          Unit = "Count"
+	UnitCPU            Unit = "CPU"
+	UnitSpeedDisk      Unit = "SpeedDisk"
+	UnitSpeedNet       Unit = "SpeedNet"
+	UnitCustom         Unit = "Custom"
+)
+
+// Result is a result of an HTTP Push Data Advanced sensor
+//
+// When Error is set, the sensor goes into the down state with Text as its message
+// and the channels may be left empty.
+type Result struct {
+	Channels []Channel
+	Text     string
+	Error    bool
+}
+
+// Channel is the value of one channel of a result
+//
+// Set Float for values that are not whole numbers, otherwise the value is rounded by PRTG.
+// CustomUnit is the unit label shown by PRTG when Unit is UnitCustom.
+// Limits are only applied by PRTG when the channel is created, so changing them later
+// has to be done in PRTG itself.
+type Channel struct {
+	Name        string
+	Value       float64
+	Float       bool
+	Unit        Unit
+	CustomUnit  string
+	Warning     bool
+	ValueLookup string
+	Limits      *Limits
+}
+
+// Limits are the warning and error limits of a channel, a nil limit is not set
+type Limits struct {
+	MaxError       *float64
+	MaxWarning     *float64
+	MinError       *float64
+	MinWarning     *float64
+	ErrorMessage   string
+	WarningMessage string
+}
+
+// Validate checks whether PRTG will accept the result
+func (r Result) Validate() error {
+	if len(r.Channels) == 0 && !r.Error {
+		return fmt.Errorf("A result needs at least one channel")
+	}
+	if length := utf8.RuneCountInString(r.Text); length > maxTextLength {
+		return fmt.Errorf("The text of a result can be at most %d characters, got %d", maxTextLength, length)
+	}
+
+	names := map[string]bool{}
+	for _, channel := range r.Channels {
+		if channel.Name == "" {
+			return fmt.Errorf("Every channel needs a name")
+		}
+		if names[channel.Name] {
+			return fmt.Errorf("Channel %q is in the result more than once", channel.Name)
+		}
+		names[channel.Name] = true
+
+		if !channel.Float && channel.Value != float64(int64(channel.Value)) {
+			return fmt.Errorf("Channel %q has value %v, which is not a whole number, set Float for it", channel.Name, channel.Value)
+		}
+
+		if channel.Limits != nil {
+			err := channel.Limits.validate()
+			if err != nil {
+				return fmt.Errorf("Invalid limits for channel %q: %v", channel.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validate checks that the limits that are set are in order from the minimum error limit
+// up to the maximum error limit, the same rules prtgapi applies when setting channel limits
+func (l *Limits) validate() error {
+	limits := []struct {
+		name  string
+		value *float64
+	}{
+		{"minimum error", l.MinError},
+		{"minimum warning", l.MinWarning},
+		{"maximum warning", l.MaxWarning},
+		{"maximum error", l.MaxError},
+	}
+
+	for i, lower := range limits {
+		for _, upper := range limits[i+1:] {
+			if lower.value != nil && upper.value != nil && *lower.value >= *upper.value {
+				return fmt.Errorf("The %s limit (%v) must be below the %s limit (%v)", lower.name, *lower.value, upper.name, *upper.value)
+			}
+		}
+	}
+	return nil
+}
+
+// pushResult is the structure PRTG expects, shared by the JSON and XML encoding
+type pushResult struct {
+	XMLName  xml.Name      `json:"-" xml:"prtg"`
+	Channels []pushChannel `json:"result,omitempty" xml:"result"`
+	Text     string        `json:"text,omitempty" xml:"text,omitempty"`
+	Error    int           `json:"error,omitempty" xml:"error,omitempty"`
+}
+
+type pushChannel struct {
+	Channel         string      `json:"channel" xml:"channel"`
+	Value           json.Number `json:"value" xml:"value"`
+	Float           int         `json:"float,omitempty" xml:"float,omitempty"`
+	Unit            Unit        `json:"unit,omitempty" xml:"unit,omitempty"`
+	CustomUnit      string      `json:"customunit,omitempty" xml:"customunit,omitempty"`
+	Warning         int         `json:"warning,omitempty" xml:"warning,omitempty"`
+	ValueLookup     string      `json:"valuelookup,omitempty" xml:"valuelookup,omitempty"`
+	LimitMode       int         `json:"limitmode,omitempty" xml:"limitmode,omitempty"`
+	LimitMaxError   string      `json:"limitmaxerror,omitempty" xml:"limitmaxerror,omitempty"`
+	LimitMaxWarning string      `json:"limitmaxwarning,omitempty" xml:"limitmaxwarning,omitempty"`
+	LimitMinError   string      `json:"limitminerror,omitempty" xml:"limitminerror,omitempty"`
+	LimitMinWarning string      `json:"limitminwarning,omitempty" xml:"limitminwarning,omitempty"`
+	LimitErrorMsg   string      `json:"limiterrormsg,omitempty" xml:"limiterrormsg,omitempty"`
+	LimitWarningMsg string      `json:"limitwarningmsg,omitempty" xml:"limitwarningmsg,omitempty"`
+}
+
+func newPushResult(r Result) pushResult {
+	result := pushResult{Text: r.Text}
+	if r.Error {
+		result.Error = 1
+	}
+
+	for _, channel := range r.Channels {
+		c := pushChannel{
+			Channel:     channel.Name,
+			Value:       json.Number(strconv.FormatFloat(channel.Value, 'f', -1, 64)),
+			Unit:        channel.Unit,
+			CustomUnit:  channel.CustomUnit,
+			ValueLookup: channel.ValueLookup,
+		}
+		if channel.Float {
+			c.Float = 1
+		}
+		if channel.Warning {
+			c.Warning = 1
+		}
+		if limits := channel.Limits; limits != nil {
+			c.LimitMode = 1
+			c.LimitMaxError = formatLimit(limits.MaxError)
+			c.LimitMaxWarning = formatLimit(limits.MaxWarning)
+			c.LimitMinError = formatLimit(limits.MinError)
+			c.LimitMinWarning = formatLimit(limits.MinWarning)
+			c.LimitErrorMsg = limits.ErrorMessage
+			c.LimitWarningMsg = limits.WarningMessage
+		}
+		result.Channels = append(result.Channels, c)
+	}
+
+	return result
+}
+
+func formatLimit(limit *float64) string {
+	if limit == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*limit, 'f', -1, 64)
+}
+
+// encodeJSON encodes a result in the JSON format of PRTG, which wraps the result in a prtg object
+func encodeJSON(r Result) ([]byte, error) {
+	return json.Marshal(struct {
+		PRTG pushResult `json:"prtg"`
+	}{newPushResult(r)})
+}
+
+// encodeXML encodes a result in the XML format of PRTG
+func encodeXML(r Result) ([]byte, error) {
+	body, err := xml.Marshal(newPushResult(r))
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), body...), nil
+}
@@ -0,0 +1,26 @@
+/*
+Package prtgreport computes service level reports from the historic data in PRTG
+
+For every sensor the availability, the downtime intervals, the mean time to repair
+and optionally latency percentiles are computed over a period. Reports can be made
+for a single sensor or for all sensors in a group and rendered as JSON or CSV.
+
+Sample usage
+
+	reporter := &prtgreport.Reporter{
+		Client:         prtgclient,
+		LatencyChannel: "Response Time",
+	}
+
+	from := time.Date(2019, 11, 1, 0, 0, 0, 0, time.UTC)
+	report, err := reporter.Group(ctx, 900, from, from.AddDate(0, 1, 0))
+	if err != nil {
+		log.Fatalf("Unable to create report: %v", err)
+	}
+
+	err = report.WriteCSV(os.Stdout)
+
+The availability is based on the Downtime channel PRTG keeps for every sensor.
+This channel is only available in averaged data, so the average interval must be positive.
+*/
+package prtgreport
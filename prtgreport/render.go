@@ -0,0 +1,143 @@
+package prtgreport
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+)
+
+type jsonReport struct {
+	GroupID       int64         `json:"group_id,omitempty"`
+	From          time.Time     `json:"from"`
+	To            time.Time     `json:"to"`
+	Availability  float64       `json:"availability"`
+	MonitoredSecs float64       `json:"monitored_seconds"`
+	DowntimeSecs  float64       `json:"downtime_seconds"`
+	Incidents     int           `json:"incidents"`
+	MTTRSecs      float64       `json:"mttr_seconds"`
+	Sensors       []*jsonSensor `json:"sensors"`
+}
+
+type jsonSensor struct {
+	SensorID          int64          `json:"sensor_id"`
+	SensorName        string         `json:"sensor_name,omitempty"`
+	Availability      float64        `json:"availability"`
+	MonitoredSecs     float64        `json:"monitored_seconds"`
+	DowntimeSecs      float64        `json:"downtime_seconds"`
+	MTTRSecs          float64        `json:"mttr_seconds"`
+	DowntimeIntervals []jsonInterval `json:"downtime_intervals"`
+	Latency           []jsonLatency  `json:"latency,omitempty"`
+}
+
+type jsonInterval struct {
+	Start        time.Time `json:"start"`
+	End          time.Time `json:"end"`
+	DowntimeSecs float64   `json:"downtime_seconds"`
+}
+
+type jsonLatency struct {
+	Percentile float64 `json:"percentile"`
+	Value      float64 `json:"value"`
+}
+
+// WriteJSON writes the report as JSON, durations are written in seconds
+func (r *Report) WriteJSON(w io.Writer) error {
+	report := &jsonReport{
+		GroupID:       r.GroupID,
+		From:          r.From,
+		To:            r.To,
+		Availability:  r.Availability,
+		MonitoredSecs: r.Monitored.Seconds(),
+		DowntimeSecs:  r.Downtime.Seconds(),
+		Incidents:     r.Incidents,
+		MTTRSecs:      r.MTTR.Seconds(),
+		Sensors:       make([]*jsonSensor, len(r.Sensors)),
+	}
+
+	for i, sensor := range r.Sensors {
+		s := &jsonSensor{
+			SensorID:          sensor.SensorID,
+			SensorName:        sensor.SensorName,
+			Availability:      sensor.Availability,
+			MonitoredSecs:     sensor.Monitored.Seconds(),
+			DowntimeSecs:      sensor.Downtime.Seconds(),
+			MTTRSecs:          sensor.MTTR.Seconds(),
+			DowntimeIntervals: make([]jsonInterval, len(sensor.DowntimeIntervals)),
+		}
+		for j, interval := range sensor.DowntimeIntervals {
+			s.DowntimeIntervals[j] = jsonInterval{
+				Start:        interval.Start,
+				End:          interval.End,
+				DowntimeSecs: interval.Downtime.Seconds(),
+			}
+		}
+		for _, latency := range sensor.Latency {
+			s.Latency = append(s.Latency, jsonLatency(latency))
+		}
+		report.Sensors[i] = s
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+// WriteCSV writes the report as CSV with a header and a row per sensor.
+// Durations are written in seconds, the latency percentiles get a column each.
+func (r *Report) WriteCSV(w io.Writer) error {
+	var percentiles []float64
+	for _, sensor := range r.Sensors {
+		if len(sensor.Latency) > len(percentiles) {
+			percentiles = percentiles[:0]
+			for _, latency := range sensor.Latency {
+				percentiles = append(percentiles, latency.Percentile)
+			}
+		}
+	}
+
+	header := []string{"sensor_id", "sensor_name", "from", "to", "availability", "monitored_seconds", "downtime_seconds", "incidents", "mttr_seconds"}
+	for _, p := range percentiles {
+		header = append(header, "latency_p"+formatFloat(p))
+	}
+
+	writer := csv.NewWriter(w)
+	err := writer.Write(header)
+	if err != nil {
+		return err
+	}
+
+	for _, sensor := range r.Sensors {
+		row := []string{
+			strconv.FormatInt(sensor.SensorID, 10),
+			sensor.SensorName,
+			r.From.Format(time.RFC3339),
+			r.To.Format(time.RFC3339),
+			formatFloat(sensor.Availability),
+			formatFloat(sensor.Monitored.Seconds()),
+			formatFloat(sensor.Downtime.Seconds()),
+			strconv.Itoa(len(sensor.DowntimeIntervals)),
+			formatFloat(sensor.MTTR.Seconds()),
+		}
+		for i := range percentiles {
+			value := ""
+			if i < len(sensor.Latency) {
+				value = formatFloat(sensor.Latency[i].Value)
+			}
+			row = append(row, value)
+		}
+
+		err = writer.Write(row)
+		if err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
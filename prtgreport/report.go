@@ -0,0 +1,270 @@
+package prtgreport
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/youngcapital/go-prtg/prtgapi"
+)
+
+const (
+	defaultAverage         = 5 * time.Minute
+	defaultDowntimeChannel = "Downtime"
+)
+
+var defaultPercentiles = []float64{50, 90, 99}
+
+// Reporter creates service level reports from the historic data in PRTG
+//
+// Average is the interval the historic data is averaged over, it defaults to 5 minutes
+// and determines the precision of the downtime intervals. DowntimeChannel is the name
+// of the channel holding the downtime percentage and defaults to "Downtime".
+// When LatencyChannel is set, the Percentiles (default 50, 90 and 99) of that channel
+// are added to the report of every sensor that has the channel.
+type Reporter struct {
+	Client *prtgapi.Client
+
+	Average         time.Duration
+	DowntimeChannel string
+	LatencyChannel  string
+	Percentiles     []float64
+}
+
+// Report holds the service level report of one or more sensors over a period
+//
+// The totals are computed over all sensors in the report. Availability is the percentage
+// of the monitored time the sensors were up, it is 0 when there is no monitoring data.
+type Report struct {
+	GroupID int64
+	From    time.Time
+	To      time.Time
+
+	Availability float64
+	Monitored    time.Duration
+	Downtime     time.Duration
+	Incidents    int
+	MTTR         time.Duration
+
+	Sensors []*SensorReport
+}
+
+// SensorReport holds the service level report of a single sensor
+//
+// Every downtime interval counts as an incident, MTTR is the mean downtime per incident.
+type SensorReport struct {
+	SensorID   int64
+	SensorName string
+
+	Availability      float64
+	Monitored         time.Duration
+	Downtime          time.Duration
+	DowntimeIntervals []Interval
+	MTTR              time.Duration
+	Latency           []Percentile
+}
+
+// Interval is a period in which a sensor was (partially) down
+//
+// Start and End are the bounds of the averaged intervals in which PRTG registered downtime,
+// Downtime is the actual time the sensor was down within those bounds.
+type Interval struct {
+	Start    time.Time
+	End      time.Time
+	Downtime time.Duration
+}
+
+// Percentile holds the value of the latency channel at a percentile
+type Percentile struct {
+	Percentile float64
+	Value      float64
+}
+
+// Sensor creates the report of a single sensor
+func (r *Reporter) Sensor(ctx context.Context, sensorID int64, from time.Time, to time.Time) (*Report, error) {
+	sensor, err := r.Client.Sensors().GetByID(ctx, sensorID, prtgapi.SensorListOptions{
+		Columns: []string{"objid", "name"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if sensor == nil {
+		return nil, fmt.Errorf("Sensor %d was not found", sensorID)
+	}
+
+	sensorReport, err := r.sensor(ctx, sensorID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	sensorReport.SensorName = sensor.Name
+
+	return newReport(0, from, to, []*SensorReport{sensorReport}), nil
+}
+
+// Group creates a report of all sensors in a group, including the sensors in its subgroups
+func (r *Reporter) Group(ctx context.Context, groupID int64, from time.Time, to time.Time) (*Report, error) {
+	sensors, err := r.Client.Sensors().List(ctx, prtgapi.SensorListOptions{
+		ParentGroupID: groupID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sensorReports := make([]*SensorReport, 0, len(sensors))
+	for _, sensor := range sensors {
+		sensorReport, err := r.sensor(ctx, sensor.ID, from, to)
+		if err != nil {
+			return nil, fmt.Errorf("Error while creating the report of sensor %d: %v", sensor.ID, err)
+		}
+		sensorReport.SensorName = sensor.Name
+		sensorReports = append(sensorReports, sensorReport)
+	}
+
+	return newReport(groupID, from, to, sensorReports), nil
+}
+
+func (r *Reporter) sensor(ctx context.Context, sensorID int64, from time.Time, to time.Time) (*SensorReport, error) {
+	average := r.Average
+	if average == 0 {
+		average = defaultAverage
+	}
+	if average < 0 {
+		return nil, fmt.Errorf("The average interval must be positive to compute the downtime")
+	}
+
+	history, err := r.Client.Sensors().History(ctx, sensorID, from, to, average)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.Compute(history)
+}
+
+// Compute creates the report of a sensor from its historic data
+func (r *Reporter) Compute(history *prtgapi.History) (*SensorReport, error) {
+	if history.Average <= 0 {
+		return nil, fmt.Errorf("The downtime can't be computed from raw data, the history must be averaged")
+	}
+
+	downtimeChannel := r.DowntimeChannel
+	if downtimeChannel == "" {
+		downtimeChannel = defaultDowntimeChannel
+	}
+
+	report := &SensorReport{
+		SensorID: history.SensorID,
+	}
+
+	if downtime := history.Channel(downtimeChannel); downtime != nil {
+		computeDowntime(report, downtime.Points, history.Average)
+	}
+
+	// Not every sensor in a group measures latency, e.g. a ping sensor next to an HTTP sensor,
+	// so the percentiles are left empty for sensors without the channel
+	if r.LatencyChannel != "" {
+		if latency := history.Channel(r.LatencyChannel); latency != nil {
+			percentiles := r.Percentiles
+			if len(percentiles) == 0 {
+				percentiles = defaultPercentiles
+			}
+			report.Latency = computePercentiles(latency.Points, percentiles)
+		}
+	}
+
+	return report, nil
+}
+
+// computeDowntime fills the availability, downtime and MTTR from the downtime percentages.
+// Every point holds the percentage of downtime in the average interval that ends at its time.
+// Only the part of the interval covered by monitoring data counts as monitored.
+func computeDowntime(report *SensorReport, points []prtgapi.HistoryPoint, average time.Duration) {
+	var current *Interval
+
+	for _, point := range points {
+		monitored := time.Duration(math.Max(0, math.Min(point.Coverage, 100)) / 100 * float64(average))
+		if !point.HasValue || monitored == 0 {
+			current = nil
+			continue
+		}
+		report.Monitored += monitored
+
+		if point.Value <= 0 {
+			current = nil
+			continue
+		}
+
+		downtime := time.Duration(math.Min(point.Value, 100) / 100 * float64(monitored))
+		report.Downtime += downtime
+
+		start := point.Time.Add(-average)
+		if current == nil || current.End.Before(start) {
+			report.DowntimeIntervals = append(report.DowntimeIntervals, Interval{Start: start})
+		}
+		current = &report.DowntimeIntervals[len(report.DowntimeIntervals)-1]
+		current.End = point.Time
+		current.Downtime += downtime
+	}
+
+	report.Availability = availability(report.Monitored, report.Downtime)
+	report.MTTR = mttr(report.Downtime, len(report.DowntimeIntervals))
+}
+
+// computePercentiles returns the percentiles of the values, interpolating between the closest ranks
+func computePercentiles(points []prtgapi.HistoryPoint, percentiles []float64) []Percentile {
+	var values []float64
+	for _, point := range points {
+		if point.HasValue {
+			values = append(values, point.Value)
+		}
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	sort.Float64s(values)
+
+	result := make([]Percentile, len(percentiles))
+	for i, p := range percentiles {
+		rank := math.Max(0, math.Min(100, p)) / 100 * float64(len(values)-1)
+		lower := int(math.Floor(rank))
+		upper := int(math.Ceil(rank))
+		value := values[lower] + (values[upper]-values[lower])*(rank-float64(lower))
+		result[i] = Percentile{Percentile: p, Value: value}
+	}
+
+	return result
+}
+
+func newReport(groupID int64, from time.Time, to time.Time, sensors []*SensorReport) *Report {
+	report := &Report{
+		GroupID: groupID,
+		From:    from,
+		To:      to,
+		Sensors: sensors,
+	}
+
+	for _, sensor := range sensors {
+		report.Monitored += sensor.Monitored
+		report.Downtime += sensor.Downtime
+		report.Incidents += len(sensor.DowntimeIntervals)
+	}
+	report.Availability = availability(report.Monitored, report.Downtime)
+	report.MTTR = mttr(report.Downtime, report.Incidents)
+
+	return report
+}
+
+func availability(monitored time.Duration, downtime time.Duration) float64 {
+	if monitored == 0 {
+		return 0
+	}
+	return 100 * float64(monitored-downtime) / float64(monitored)
+}
+
+func mttr(downtime time.Duration, incidents int) time.Duration {
+	if incidents == 0 {
+		return 0
+	}
+	return downtime / time.Duration(incidents)
+}
@@ -0,0 +1,159 @@
+package prtgreport
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/youngcapital/go-prtg/prtgapi"
+)
+
+var start = time.Date(2019, 11, 1, 0, 0, 0, 0, time.UTC)
+
+func testHistory() *prtgapi.History {
+	point := func(i int, value float64) prtgapi.HistoryPoint {
+		return prtgapi.HistoryPoint{Time: start.Add(time.Duration(i+1) * time.Hour), Value: value, HasValue: true, Coverage: 100}
+	}
+
+	return &prtgapi.History{
+		SensorID: 2345,
+		From:     start,
+		To:       start.Add(8 * time.Hour),
+		Average:  time.Hour,
+		Channels: []*prtgapi.ChannelHistory{
+			{Name: "Downtime", Points: []prtgapi.HistoryPoint{
+				point(0, 0),
+				point(1, 50),
+				point(2, 100),
+				point(3, 0),
+				point(4, 25),
+				{Time: start.Add(6 * time.Hour)},
+				point(6, 0),
+				point(7, 0),
+			}},
+			{Name: "Response Time", Points: []prtgapi.HistoryPoint{
+				point(0, 100),
+				point(1, 300),
+				{Time: start.Add(3 * time.Hour)},
+				point(3, 200),
+				point(4, 400),
+			}},
+		},
+	}
+}
+
+func TestReporter_Compute(t *testing.T) {
+	reporter := &Reporter{
+		LatencyChannel: "Response Time",
+		Percentiles:    []float64{50, 100},
+	}
+
+	got, err := reporter.Compute(testHistory())
+	if err != nil {
+		t.Fatalf("Error while computing report: %v", err)
+	}
+
+	want := &SensorReport{
+		SensorID:     2345,
+		Availability: 75,
+		Monitored:    7 * time.Hour,
+		Downtime:     105 * time.Minute,
+		DowntimeIntervals: []Interval{
+			{Start: start.Add(time.Hour), End: start.Add(3 * time.Hour), Downtime: 90 * time.Minute},
+			{Start: start.Add(4 * time.Hour), End: start.Add(5 * time.Hour), Downtime: 15 * time.Minute},
+		},
+		MTTR: 52*time.Minute + 30*time.Second,
+		Latency: []Percentile{
+			{Percentile: 50, Value: 250},
+			{Percentile: 100, Value: 400},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Got %+v, expected %+v", got, want)
+	}
+
+	// Sensors without the latency channel get no percentiles
+	ping := testHistory()
+	ping.Channels = ping.Channels[:1]
+	got, err = reporter.Compute(ping)
+	if err != nil {
+		t.Fatalf("Error while computing report without latency channel: %v", err)
+	}
+	if got.Latency != nil || got.Availability != 75 {
+		t.Errorf("Expected a report without latency, got %+v", got)
+	}
+
+	raw := testHistory()
+	raw.Average = 0
+	_, err = reporter.Compute(raw)
+	if err == nil {
+		t.Errorf("Expected an error when computing a report from raw data")
+	}
+}
+
+func TestReporter_Compute_Coverage(t *testing.T) {
+	point := func(i int, value float64, coverage float64) prtgapi.HistoryPoint {
+		return prtgapi.HistoryPoint{Time: start.Add(time.Duration(i+1) * time.Hour), Value: value, HasValue: true, Coverage: coverage}
+	}
+	history := &prtgapi.History{
+		SensorID: 2345,
+		From:     start,
+		To:       start.Add(3 * time.Hour),
+		Average:  time.Hour,
+		Channels: []*prtgapi.ChannelHistory{
+			{Name: "Downtime", Points: []prtgapi.HistoryPoint{
+				point(0, 0, 100),
+				point(1, 50, 50),
+				point(2, 0, 0),
+			}},
+		},
+	}
+
+	got, err := (&Reporter{}).Compute(history)
+	if err != nil {
+		t.Fatalf("Error while computing report: %v", err)
+	}
+	if got.Monitored != 90*time.Minute || got.Downtime != 15*time.Minute {
+		t.Errorf("Expected 90 minutes monitored with 15 minutes downtime, got %s monitored with %s downtime", got.Monitored, got.Downtime)
+	}
+}
+
+func TestReport_Write(t *testing.T) {
+	reporter := &Reporter{
+		LatencyChannel: "Response Time",
+		Percentiles:    []float64{50},
+	}
+	sensorReport, err := reporter.Compute(testHistory())
+	if err != nil {
+		t.Fatalf("Error while computing report: %v", err)
+	}
+	sensorReport.SensorName = "HTTP"
+	report := newReport(900, start, start.Add(8*time.Hour), []*SensorReport{sensorReport})
+
+	var csv bytes.Buffer
+	err = report.WriteCSV(&csv)
+	if err != nil {
+		t.Fatalf("Error while writing CSV: %v", err)
+	}
+	wantCSV := "sensor_id,sensor_name,from,to,availability,monitored_seconds,downtime_seconds,incidents,mttr_seconds,latency_p50\n" +
+		"2345,HTTP,2019-11-01T00:00:00Z,2019-11-01T08:00:00Z,75,25200,6300,2,3150,250\n"
+	if csv.String() != wantCSV {
+		t.Errorf("Got CSV\n%s\nexpected\n%s", csv.String(), wantCSV)
+	}
+
+	var buf bytes.Buffer
+	err = report.WriteJSON(&buf)
+	if err != nil {
+		t.Fatalf("Error while writing JSON: %v", err)
+	}
+	var decoded map[string]interface{}
+	err = json.Unmarshal(buf.Bytes(), &decoded)
+	if err != nil {
+		t.Fatalf("Invalid JSON written: %v", err)
+	}
+	if decoded["group_id"] != float64(900) || decoded["availability"] != float64(75) || decoded["incidents"] != float64(2) {
+		t.Errorf("Unexpected JSON report %s", buf.String())
+	}
+}
@@ -33,21 +33,14 @@ func (s *Syncer) Sync(ctx context.Context, v interface{}) (*SyncResult, error) {
 	if device == nil {
 		deviceName := s.DeviceNameGetter(v)
 		hostname := s.DeviceHostnameGetter(v)
-		device, err = s.Client.Devices().Duplicate(ctx, s.TemplateDeviceID, s.ParentGroupID, deviceName, hostname, identifyingTags)
+		device, err = s.Client.Devices().Duplicate(ctx, s.TemplateDeviceID, s.ParentGroupID, deviceName, hostname, prtgapi.DuplicateOptions{
+			Tags:    identifyingTags,
+			Unpause: s.UnpauseDeviceAfterCreation,
+		})
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("Error while creating device %s: %w", deviceName, err)
 		}
 		result.NewDevice = true
-		if device == nil {
-			return nil, fmt.Errorf("Created device %s, but no device was found when getting it from the API", deviceName)
-		}
-
-		if s.UnpauseDeviceAfterCreation {
-			err = s.Client.Devices().Unpause(ctx, device.ID)
-			if err != nil {
-				return nil, fmt.Errorf("Error while unpausing device %s: %v", deviceName, err)
-			}
-		}
 	}
 
 	// Compare hostname and update if necessary